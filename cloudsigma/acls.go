@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"sort"
+	"strings"
 )
 
 const aclsBasePath = "acls"
@@ -14,7 +16,8 @@ const aclsBasePath = "acls"
 // CloudSigma API docs: https://cloudsigma-docs.readthedocs.io/en/latest/acls.html
 type ACLsService service
 
-// ACL represents a CloudSigma ACL.
+// ACL represents a CloudSigma ACL. An ACL grants the permissions of its rules
+// on the resources of its tags.
 type ACL struct {
 	Meta        map[string]interface{} `json:"meta,omitempty"`
 	Name        string                 `json:"name,omitempty"`
@@ -25,9 +28,81 @@ type ACL struct {
 	UUID        string                 `json:"uuid,omitempty"`
 }
 
-// ACLRule represents a CloudSigma ACL rule.
+// ACLRule represents a CloudSigma ACL rule granting a permission to a user.
 type ACLRule struct {
-	Permission string `json:"permission,omitempty"`
+	Grantee    *ACLGrantee   `json:"grantee,omitempty"`
+	Permission ACLPermission `json:"permission,omitempty"`
+}
+
+// ACLGranteeType represents the way the grantee of an ACL rule is identified.
+type ACLGranteeType string
+
+// ACL grantee types.
+const (
+	ACLGranteeTypeEmail ACLGranteeType = "email"
+	ACLGranteeTypeUser  ACLGranteeType = "user"
+)
+
+// ACLGrantee represents the user an ACL rule grants a permission to. Value
+// is the email address or the UUID of the user, depending on Type.
+type ACLGrantee struct {
+	Type  ACLGranteeType `json:"type,omitempty"`
+	Value string         `json:"value,omitempty"`
+}
+
+// ACLGranteeEmail returns a grantee identified by email address.
+func ACLGranteeEmail(email string) *ACLGrantee {
+	return &ACLGrantee{Type: ACLGranteeTypeEmail, Value: email}
+}
+
+// ACLGranteeUser returns a grantee identified by user UUID.
+func ACLGranteeUser(uuid string) *ACLGrantee {
+	return &ACLGrantee{Type: ACLGranteeTypeUser, Value: uuid}
+}
+
+// Matches reports whether g and other identify the same user. Email
+// addresses are compared case-insensitively.
+func (g *ACLGrantee) Matches(other *ACLGrantee) bool {
+	if g == nil || other == nil || g.Type != other.Type {
+		return false
+	}
+	if g.Type == ACLGranteeTypeEmail {
+		return strings.EqualFold(g.Value, other.Value)
+	}
+	return g.Value == other.Value
+}
+
+// ACLPermission represents a permission granted by an ACL rule.
+type ACLPermission string
+
+// ACL permissions.
+const (
+	ACLPermissionAttach  ACLPermission = "ATTACH"
+	ACLPermissionEdit    ACLPermission = "EDIT"
+	ACLPermissionList    ACLPermission = "LIST"
+	ACLPermissionOpenVNC ACLPermission = "OPEN_VNC"
+	ACLPermissionStart   ACLPermission = "START"
+	ACLPermissionStop    ACLPermission = "STOP"
+)
+
+// ACLPermissions lists all ACL permissions known to the SDK.
+var ACLPermissions = []ACLPermission{
+	ACLPermissionAttach,
+	ACLPermissionEdit,
+	ACLPermissionList,
+	ACLPermissionOpenVNC,
+	ACLPermissionStart,
+	ACLPermissionStop,
+}
+
+// IsValid reports whether the permission is known to the SDK.
+func (p ACLPermission) IsValid() bool {
+	for _, permission := range ACLPermissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
 }
 
 // ACLCreateRequest represents a request to create an ACL.
@@ -52,8 +127,12 @@ func (a ACL) String() string {
 // List provides a list of ACLs defined by the authenticated user.
 //
 // CloudSigma API docs: https://cloudsigma-docs.readthedocs.io/en/latest/acls.html#listing
-func (s *ACLsService) List(ctx context.Context) ([]ACL, *Response, error) {
+func (s *ACLsService) List(ctx context.Context, opts *ListOptions) ([]ACL, *Response, error) {
 	path := fmt.Sprintf("%v/", aclsBasePath)
+	path, err := addOptions(path, opts)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	req, err := s.client.NewRequest(http.MethodGet, path, nil)
 	if err != nil {
@@ -164,3 +243,216 @@ func (s *ACLsService) Delete(ctx context.Context, uuid string) (*Response, error
 
 	return s.client.Do(ctx, req, nil)
 }
+
+// ShareTag grants the permissions on the resources of a tag to the grantee.
+// Missing permissions are added to an ACL of only this tag which already has
+// rules for the grantee, or a new ACL is created for the tag. ACLs of several
+// tags are left unchanged, as they would grant the permissions on the other
+// tags as well. The updated or created ACL is returned with the response of
+// the last request.
+func (s *ACLsService) ShareTag(ctx context.Context, tagUUID string, grantee *ACLGrantee, permissions ...ACLPermission) (*ACL, *Response, error) {
+	if tagUUID == "" || grantee == nil || grantee.Value == "" || len(permissions) == 0 {
+		return nil, nil, ErrEmptyArgument
+	}
+	for _, p := range permissions {
+		if !p.IsValid() {
+			return nil, nil, fmt.Errorf("cloudsigma-sdk-go: unknown ACL permission %q", p)
+		}
+	}
+
+	acls, resp, err := s.List(ctx, &ListOptions{Limit: 0})
+	if err != nil {
+		return nil, resp, err
+	}
+	for i := range acls {
+		acl := &acls[i]
+		granted := acl.permissions(grantee)
+		if !acl.onlyTag(tagUUID) || len(granted) == 0 {
+			continue
+		}
+		var missing bool
+		for _, p := range permissions {
+			if !granted[p] {
+				granted[p] = true
+				acl.Rules = append(acl.Rules, ACLRule{Grantee: grantee, Permission: p})
+				missing = true
+			}
+		}
+		if !missing {
+			return acl, resp, nil
+		}
+		return s.Update(ctx, acl.UUID, &ACLUpdateRequest{ACL: acl})
+	}
+
+	acl := ACL{
+		Name: fmt.Sprintf("share %v with %v", tagUUID, grantee.Value),
+		Tags: []Tag{{UUID: tagUUID}},
+	}
+	for _, p := range permissions {
+		acl.Rules = append(acl.Rules, ACLRule{Grantee: grantee, Permission: p})
+	}
+	created, resp, err := s.Create(ctx, &ACLCreateRequest{ACLs: []ACL{acl}})
+	if err != nil {
+		return nil, resp, err
+	}
+	if len(created) == 0 {
+		return nil, resp, ErrResourceNotFound
+	}
+	return &created[0], resp, nil
+}
+
+// UnshareTag revokes permissions on the resources of a tag from the grantee,
+// or all permissions if none are given. Rules are removed from every ACL of
+// the tag, and ACLs left without rules are deleted. An ACL of several tags
+// keeps its rules for the other tags: the tag is removed from it, and the
+// remaining rules are moved to a new ACL of the tag. The response of the last
+// request is returned.
+func (s *ACLsService) UnshareTag(ctx context.Context, tagUUID string, grantee *ACLGrantee, permissions ...ACLPermission) (*Response, error) {
+	if tagUUID == "" || grantee == nil || grantee.Value == "" {
+		return nil, ErrEmptyArgument
+	}
+	revoked := make(map[ACLPermission]bool, len(permissions))
+	for _, p := range permissions {
+		revoked[p] = true
+	}
+
+	acls, resp, err := s.List(ctx, &ListOptions{Limit: 0})
+	if err != nil {
+		return resp, err
+	}
+	for i := range acls {
+		acl := &acls[i]
+		if !acl.hasTag(tagUUID) {
+			continue
+		}
+		rules := make([]ACLRule, 0, len(acl.Rules))
+		for _, rule := range acl.Rules {
+			if !rule.Grantee.Matches(grantee) || len(revoked) > 0 && !revoked[rule.Permission] {
+				rules = append(rules, rule)
+			}
+		}
+		if len(rules) == len(acl.Rules) {
+			continue
+		}
+
+		switch {
+		case !acl.onlyTag(tagUUID):
+			resp, err = s.splitTag(ctx, acl, tagUUID, rules)
+		case len(rules) == 0:
+			resp, err = s.Delete(ctx, acl.UUID)
+		default:
+			acl.Rules = rules
+			_, resp, err = s.Update(ctx, acl.UUID, &ACLUpdateRequest{ACL: acl})
+		}
+		if err != nil {
+			return resp, err
+		}
+	}
+	return resp, nil
+}
+
+// splitTag removes a tag from an ACL of several tags, and creates a new ACL
+// of the tag with the given rules, if any.
+func (s *ACLsService) splitTag(ctx context.Context, acl *ACL, tagUUID string, rules []ACLRule) (*Response, error) {
+	if len(rules) > 0 {
+		split := ACL{Name: acl.Name, Rules: rules, Tags: []Tag{{UUID: tagUUID}}}
+		_, resp, err := s.Create(ctx, &ACLCreateRequest{ACLs: []ACL{split}})
+		if err != nil {
+			return resp, err
+		}
+	}
+
+	tags := make([]Tag, 0, len(acl.Tags))
+	for _, tag := range acl.Tags {
+		if tag.UUID != tagUUID {
+			tags = append(tags, tag)
+		}
+	}
+	acl.Tags = tags
+	_, resp, err := s.Update(ctx, acl.UUID, &ACLUpdateRequest{ACL: acl})
+	return resp, err
+}
+
+// EffectivePermissions returns the permissions the grantee has on a
+// resource, sorted, with the response of the last request. It walks the tags
+// of the resource and the ACLs of those tags. Rules only match grantees of the
+// same type, so users granted by UUID and by email need to be resolved
+// separately.
+//
+// The owner of a resource has all permissions, if the grantee is identified
+// by user UUID. The owner is only known from the resource listings of tags,
+// so no permissions are returned for a resource without tags, even to its
+// owner; compare the owner of the resource itself in that case.
+func (s *ACLsService) EffectivePermissions(ctx context.Context, grantee *ACLGrantee, resourceUUID string) ([]ACLPermission, *Response, error) {
+	if grantee == nil || grantee.Value == "" || resourceUUID == "" {
+		return nil, nil, ErrEmptyArgument
+	}
+
+	tags, resp, err := s.client.Tags.List(ctx, &ListOptions{Limit: 0})
+	if err != nil {
+		return nil, resp, err
+	}
+	resourceTags := make(map[string]bool)
+	for _, tag := range tags {
+		for _, resource := range tag.Resources {
+			if resource.UUID != resourceUUID {
+				continue
+			}
+			resourceTags[tag.UUID] = true
+			if grantee.Type == ACLGranteeTypeUser && resource.Owner != nil && resource.Owner.UUID == grantee.Value {
+				return append([]ACLPermission(nil), ACLPermissions...), resp, nil
+			}
+		}
+	}
+	if len(resourceTags) == 0 {
+		return nil, resp, nil
+	}
+
+	acls, resp, err := s.List(ctx, &ListOptions{Limit: 0})
+	if err != nil {
+		return nil, resp, err
+	}
+	granted := make(map[ACLPermission]bool)
+	for i := range acls {
+		for tagUUID := range resourceTags {
+			if acls[i].hasTag(tagUUID) {
+				for p := range acls[i].permissions(grantee) {
+					granted[p] = true
+				}
+				break
+			}
+		}
+	}
+
+	var permissions []ACLPermission
+	for p := range granted {
+		permissions = append(permissions, p)
+	}
+	sort.Slice(permissions, func(i, j int) bool { return permissions[i] < permissions[j] })
+	return permissions, resp, nil
+}
+
+func (a *ACL) hasTag(uuid string) bool {
+	for _, tag := range a.Tags {
+		if tag.UUID == uuid {
+			return true
+		}
+	}
+	return false
+}
+
+// onlyTag reports whether the ACL applies to the tag and no other tag.
+func (a *ACL) onlyTag(uuid string) bool {
+	return len(a.Tags) == 1 && a.Tags[0].UUID == uuid
+}
+
+// permissions returns the permissions the ACL grants to the grantee.
+func (a *ACL) permissions(grantee *ACLGrantee) map[ACLPermission]bool {
+	granted := make(map[ACLPermission]bool)
+	for _, rule := range a.Rules {
+		if rule.Grantee.Matches(grantee) {
+			granted[rule.Permission] = true
+		}
+	}
+	return granted
+}
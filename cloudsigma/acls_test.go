@@ -23,7 +23,7 @@ func TestACLs_List(t *testing.T) {
 		},
 	}
 
-	acls, resp, err := client.ACLs.List(ctx)
+	acls, resp, err := client.ACLs.List(ctx, nil)
 
 	assert.NoError(t, err)
 	assert.Equal(t, expected, acls)
@@ -181,3 +181,247 @@ func TestACLs_Delete_emptyUUID(t *testing.T) {
 	assert.Error(t, err)
 	assert.Equal(t, ErrEmptyArgument.Error(), err.Error())
 }
+
+func TestACLs_Get_rules(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/acls/long-uuid/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"rules":[{"grantee":{"type":"email","value":"user@example.com"},"permission":"LIST"}],"uuid":"long-uuid"}`)
+	})
+
+	acl, _, err := client.ACLs.Get(ctx, "long-uuid")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []ACLRule{{Grantee: ACLGranteeEmail("user@example.com"), Permission: ACLPermissionList}}, acl.Rules)
+}
+
+func TestACLGrantee_Matches(t *testing.T) {
+	assert.True(t, ACLGranteeEmail("User@Example.com").Matches(ACLGranteeEmail("user@example.com")))
+	assert.True(t, ACLGranteeUser("user-uuid").Matches(ACLGranteeUser("user-uuid")))
+	assert.False(t, ACLGranteeUser("USER-UUID").Matches(ACLGranteeUser("user-uuid")))
+	assert.False(t, ACLGranteeUser("user-uuid").Matches(ACLGranteeEmail("user-uuid")))
+	assert.False(t, ACLGranteeUser("user-uuid").Matches(nil))
+}
+
+func TestACLPermission_IsValid(t *testing.T) {
+	assert.True(t, ACLPermissionOpenVNC.IsValid())
+	assert.False(t, ACLPermission("list").IsValid())
+}
+
+func TestACLs_ShareTag_create(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/acls/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			assert.Equal(t, "0", r.URL.Query().Get("limit"))
+			_, _ = fmt.Fprint(w, `{"objects":[{"uuid":"other-acl","tags":[{"uuid":"other-tag"}],"rules":[{"grantee":{"type":"email","value":"user@example.com"},"permission":"LIST"}]}]}`)
+			return
+		}
+		assert.Equal(t, http.MethodPost, r.Method)
+		v := new(ACLCreateRequest)
+		_ = json.NewDecoder(r.Body).Decode(v)
+		assert.Equal(t, &ACLCreateRequest{ACLs: []ACL{{
+			Name: "share tag-uuid with user@example.com",
+			Rules: []ACLRule{
+				{Grantee: ACLGranteeEmail("user@example.com"), Permission: ACLPermissionList},
+				{Grantee: ACLGranteeEmail("user@example.com"), Permission: ACLPermissionStart},
+			},
+			Tags: []Tag{{UUID: "tag-uuid"}},
+		}}}, v)
+		_, _ = fmt.Fprint(w, `{"objects":[{"uuid":"acl-uuid"}]}`)
+	})
+
+	acl, _, err := client.ACLs.ShareTag(ctx, "tag-uuid", ACLGranteeEmail("user@example.com"), ACLPermissionList, ACLPermissionStart)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "acl-uuid", acl.UUID)
+}
+
+func TestACLs_ShareTag_update(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/acls/", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "0", r.URL.Query().Get("limit"))
+		_, _ = fmt.Fprint(w, `{"objects":[{"uuid":"acl-uuid","tags":[{"uuid":"tag-uuid"}],"rules":[`+
+			`{"grantee":{"type":"user","value":"other-uuid"},"permission":"EDIT"},`+
+			`{"grantee":{"type":"user","value":"user-uuid"},"permission":"LIST"}]}]}`)
+	})
+	mux.HandleFunc("/acls/acl-uuid/", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		v := new(ACL)
+		_ = json.NewDecoder(r.Body).Decode(v)
+		assert.Equal(t, []ACLRule{
+			{Grantee: ACLGranteeUser("other-uuid"), Permission: ACLPermissionEdit},
+			{Grantee: ACLGranteeUser("user-uuid"), Permission: ACLPermissionList},
+			{Grantee: ACLGranteeUser("user-uuid"), Permission: ACLPermissionEdit},
+		}, v.Rules)
+		_, _ = fmt.Fprint(w, `{"uuid":"acl-uuid"}`)
+	})
+
+	acl, _, err := client.ACLs.ShareTag(ctx, "tag-uuid", ACLGranteeUser("user-uuid"), ACLPermissionList, ACLPermissionEdit)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "acl-uuid", acl.UUID)
+}
+
+func TestACLs_ShareTag_severalTags(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/acls/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			_, _ = fmt.Fprint(w, `{"objects":[{"uuid":"shared-acl","tags":[{"uuid":"tag-uuid"},{"uuid":"other-tag"}],"rules":[`+
+				`{"grantee":{"type":"user","value":"user-uuid"},"permission":"LIST"}]}]}`)
+			return
+		}
+		assert.Equal(t, http.MethodPost, r.Method)
+		v := new(ACLCreateRequest)
+		_ = json.NewDecoder(r.Body).Decode(v)
+		assert.Equal(t, []Tag{{UUID: "tag-uuid"}}, v.ACLs[0].Tags)
+		assert.Equal(t, []ACLRule{{Grantee: ACLGranteeUser("user-uuid"), Permission: ACLPermissionEdit}}, v.ACLs[0].Rules)
+		_, _ = fmt.Fprint(w, `{"objects":[{"uuid":"acl-uuid"}]}`)
+	})
+	mux.HandleFunc("/acls/shared-acl/", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("ACL of several tags must not be updated")
+	})
+
+	acl, _, err := client.ACLs.ShareTag(ctx, "tag-uuid", ACLGranteeUser("user-uuid"), ACLPermissionEdit)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "acl-uuid", acl.UUID)
+}
+
+func TestACLs_ShareTag_invalid(t *testing.T) {
+	_, _, err := client.ACLs.ShareTag(ctx, "tag-uuid", ACLGranteeUser("user-uuid"))
+	assert.ErrorIs(t, err, ErrEmptyArgument)
+
+	_, _, err = client.ACLs.ShareTag(ctx, "tag-uuid", ACLGranteeUser("user-uuid"), "READ")
+	assert.EqualError(t, err, `cloudsigma-sdk-go: unknown ACL permission "READ"`)
+}
+
+func TestACLs_UnshareTag(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/acls/", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "0", r.URL.Query().Get("limit"))
+		_, _ = fmt.Fprint(w, `{"objects":[`+
+			`{"uuid":"shared-acl","tags":[{"uuid":"tag-uuid"}],"rules":[`+
+			`{"grantee":{"type":"user","value":"other-uuid"},"permission":"EDIT"},`+
+			`{"grantee":{"type":"user","value":"user-uuid"},"permission":"LIST"}]},`+
+			`{"uuid":"user-acl","tags":[{"uuid":"tag-uuid"}],"rules":[{"grantee":{"type":"user","value":"user-uuid"},"permission":"START"}]},`+
+			`{"uuid":"other-tag-acl","tags":[{"uuid":"other-tag"}],"rules":[{"grantee":{"type":"user","value":"user-uuid"},"permission":"LIST"}]}`+
+			`]}`)
+	})
+	var updated, deleted bool
+	mux.HandleFunc("/acls/shared-acl/", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		v := new(ACL)
+		_ = json.NewDecoder(r.Body).Decode(v)
+		assert.Equal(t, []ACLRule{{Grantee: ACLGranteeUser("other-uuid"), Permission: ACLPermissionEdit}}, v.Rules)
+		updated = true
+		_, _ = fmt.Fprint(w, `{"uuid":"shared-acl"}`)
+	})
+	mux.HandleFunc("/acls/user-acl/", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+		deleted = true
+	})
+
+	_, err := client.ACLs.UnshareTag(ctx, "tag-uuid", ACLGranteeUser("user-uuid"))
+
+	assert.NoError(t, err)
+	assert.True(t, updated)
+	assert.True(t, deleted)
+}
+
+func TestACLs_UnshareTag_severalTags(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/acls/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			_, _ = fmt.Fprint(w, `{"objects":[`+
+				`{"uuid":"shared-acl","name":"team","tags":[{"uuid":"tag-uuid"},{"uuid":"other-tag"}],"rules":[`+
+				`{"grantee":{"type":"user","value":"other-uuid"},"permission":"EDIT"},`+
+				`{"grantee":{"type":"user","value":"user-uuid"},"permission":"LIST"}]},`+
+				`{"uuid":"user-acl","tags":[{"uuid":"other-tag"},{"uuid":"tag-uuid"}],"rules":[{"grantee":{"type":"user","value":"user-uuid"},"permission":"START"}]}`+
+				`]}`)
+			return
+		}
+		assert.Equal(t, http.MethodPost, r.Method)
+		v := new(ACLCreateRequest)
+		_ = json.NewDecoder(r.Body).Decode(v)
+		assert.Equal(t, &ACLCreateRequest{ACLs: []ACL{{
+			Name:  "team",
+			Rules: []ACLRule{{Grantee: ACLGranteeUser("other-uuid"), Permission: ACLPermissionEdit}},
+			Tags:  []Tag{{UUID: "tag-uuid"}},
+		}}}, v)
+		_, _ = fmt.Fprint(w, `{"objects":[{"uuid":"split-acl"}]}`)
+	})
+	updated := make(map[string]*ACL)
+	for _, uuid := range []string{"shared-acl", "user-acl"} {
+		mux.HandleFunc("/acls/"+uuid+"/", func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPut, r.Method)
+			v := new(ACL)
+			_ = json.NewDecoder(r.Body).Decode(v)
+			updated[uuid] = v
+			_, _ = fmt.Fprintf(w, `{"uuid":%q}`, uuid)
+		})
+	}
+
+	_, err := client.ACLs.UnshareTag(ctx, "tag-uuid", ACLGranteeUser("user-uuid"))
+
+	assert.NoError(t, err)
+	if assert.Len(t, updated, 2) {
+		assert.Equal(t, []Tag{{UUID: "other-tag"}}, updated["shared-acl"].Tags)
+		assert.Len(t, updated["shared-acl"].Rules, 2)
+		assert.Equal(t, []Tag{{UUID: "other-tag"}}, updated["user-acl"].Tags)
+		assert.Len(t, updated["user-acl"].Rules, 1)
+	}
+}
+
+func TestACLs_EffectivePermissions(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/tags/", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "0", r.URL.Query().Get("limit"))
+		_, _ = fmt.Fprint(w, `{"objects":[`+
+			`{"uuid":"web-tag","resources":[{"uuid":"server-uuid","owner":{"uuid":"owner-uuid"}}]},`+
+			`{"uuid":"prod-tag","resources":[{"uuid":"server-uuid","owner":{"uuid":"owner-uuid"}},{"uuid":"drive-uuid"}]},`+
+			`{"uuid":"other-tag","resources":[{"uuid":"drive-uuid"}]}`+
+			`]}`)
+	})
+	mux.HandleFunc("/acls/", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "0", r.URL.Query().Get("limit"))
+		_, _ = fmt.Fprint(w, `{"objects":[`+
+			`{"uuid":"web-acl","tags":[{"uuid":"web-tag"}],"rules":[`+
+			`{"grantee":{"type":"user","value":"user-uuid"},"permission":"START"},`+
+			`{"grantee":{"type":"user","value":"user-uuid"},"permission":"LIST"}]},`+
+			`{"uuid":"prod-acl","tags":[{"uuid":"prod-tag"},{"uuid":"web-tag"}],"rules":[`+
+			`{"grantee":{"type":"user","value":"user-uuid"},"permission":"LIST"},`+
+			`{"grantee":{"type":"user","value":"other-uuid"},"permission":"EDIT"}]},`+
+			`{"uuid":"other-acl","tags":[{"uuid":"other-tag"}],"rules":[{"grantee":{"type":"user","value":"user-uuid"},"permission":"EDIT"}]}`+
+			`]}`)
+	})
+
+	permissions, _, err := client.ACLs.EffectivePermissions(ctx, ACLGranteeUser("user-uuid"), "server-uuid")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []ACLPermission{ACLPermissionList, ACLPermissionStart}, permissions)
+
+	permissions, _, err = client.ACLs.EffectivePermissions(ctx, ACLGranteeUser("owner-uuid"), "server-uuid")
+
+	assert.NoError(t, err)
+	assert.Equal(t, ACLPermissions, permissions)
+
+	permissions, _, err = client.ACLs.EffectivePermissions(ctx, ACLGranteeUser("user-uuid"), "unknown-uuid")
+
+	assert.NoError(t, err)
+	assert.Empty(t, permissions)
+}
@@ -0,0 +1,41 @@
+package cloudsigma
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+const balanceBasePath = "balance"
+
+// BalanceService handles communication with the balance related methods of
+// the CloudSigma API.
+//
+// CloudSigma API docs: https://cloudsigma-docs.readthedocs.io/en/latest/billing.html#balance
+type BalanceService service
+
+// Balance represents a CloudSigma account balance.
+type Balance struct {
+	Balance  Decimal `json:"balance"`
+	Currency string  `json:"currency,omitempty"`
+}
+
+// Get provides the current balance of the user.
+//
+// CloudSigma API docs: https://cloudsigma-docs.readthedocs.io/en/latest/billing.html#balance
+func (s *BalanceService) Get(ctx context.Context) (*Balance, *Response, error) {
+	path := fmt.Sprintf("%v/", balanceBasePath)
+
+	req, err := s.client.NewRequest(http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	balance := new(Balance)
+	resp, err := s.client.Do(ctx, req, balance)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return balance, resp, nil
+}
@@ -0,0 +1,28 @@
+package cloudsigma
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBalance_Get(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/balance/", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		_, _ = fmt.Fprint(w, `{"balance":"1234.56789","currency":"CHF"}`)
+	})
+	expected := &Balance{
+		Balance:  MustParseDecimal("1234.56789"),
+		Currency: "CHF",
+	}
+
+	balance, _, err := client.Balance.Get(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, balance)
+}
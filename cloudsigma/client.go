@@ -10,6 +10,7 @@ import (
 	"net/url"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/google/go-querystring/query"
 )
@@ -19,6 +20,8 @@ const (
 	defaultLocation  = "zrh"
 	defaultUserAgent = "cloudsigma-sdk-go/" + libraryVersion
 
+	defaultPollInterval = 5 * time.Second
+
 	// endpointURL is a URL with the placeholder for API location.
 	endpointURL     = "https://%s.cloudsigma.com/api/2.0/"
 	headerRequestID = "X-REQUEST-ID"
@@ -33,22 +36,36 @@ type Client struct {
 	// can be queried from Locations endpoint.
 	baseURL *url.URL
 
+	// endpointURL is a URL with the placeholder for API location, used to
+	// create clients for other locations.
+	endpointURL string
+	location    string // Location of the API the client communicates with.
+
 	httpClient   *http.Client // HTTP client used to communicate with the API.
 	credProvider CredentialsProvider
-	userAgent    string // User agent used when communicating with the CloudSigma API.
+	userAgent    string        // User agent used when communicating with the CloudSigma API.
+	pollInterval time.Duration // Interval between API calls of methods waiting for a resource state.
+
+	serverValidator *ServerValidator // Optional validator of server definitions before create and update.
 
 	common service // Reuse a single struct instead of allocating one for each service on the heap.
 
 	ACLs             *ACLsService
+	Balance          *BalanceService
+	BurstUsage       *BurstUsageService
 	Capabilities     *CapabilitiesService
 	CloudStatus      *CloudStatusService
+	CurrentUsage     *CurrentUsageService
 	Drives           *DrivesService
 	FirewallPolicies *FirewallPoliciesService
 	IPs              *IPsService
+	Jobs             *JobsService
 	Keypairs         *KeypairsService
 	Licenses         *LicensesService
+	Ledger           *LedgerService
 	LibraryDrives    *LibraryDrivesService
 	Locations        *LocationsService
+	Pricing          *PricingService
 	Profile          *ProfileService
 	Pubkeys          *PubkeysService
 	RemoteSnapshots  *RemoteSnapshotsService
@@ -56,6 +73,7 @@ type Client struct {
 	Snapshots        *SnapshotsService
 	Subscriptions    *SubscriptionsService
 	Tags             *TagsService
+	Usage            *UsageService
 	VLANs            *VLANsService
 }
 
@@ -109,8 +127,31 @@ func WithHTTPClient(httpClient *http.Client) ClientOption {
 // WithLocation configures Client to use a specific location.
 func WithLocation(location string) ClientOption {
 	return func(client *Client) {
-		parsedURL, _ := url.Parse(fmt.Sprintf(endpointURL, location))
+		parsedURL, _ := url.Parse(fmt.Sprintf(client.endpointURL, location))
 		client.baseURL = parsedURL
+		client.location = location
+	}
+}
+
+// WithPollInterval configures Client to use a specific interval between
+// API calls of methods waiting for a resource state, e.g. DrivesService.WaitForStatus.
+// Non-positive intervals are ignored and the default interval is kept.
+func WithPollInterval(interval time.Duration) ClientOption {
+	return func(client *Client) {
+		if interval > 0 {
+			client.pollInterval = interval
+		}
+	}
+}
+
+// WithServerValidator configures Client to validate server definitions
+// against the location capabilities before ServersService.Create and
+// ServersService.Update, so invalid definitions fail locally with a
+// *ValidationError. The validator caches capabilities and can be shared by
+// several clients.
+func WithServerValidator(validator *ServerValidator) ClientOption {
+	return func(client *Client) {
+		client.serverValidator = validator
 	}
 }
 
@@ -128,26 +169,54 @@ func NewClient(cred CredentialsProvider, opts ...ClientOption) *Client {
 
 	c := &Client{
 		baseURL:      baseURL,
+		endpointURL:  endpointURL,
+		location:     defaultLocation,
 		httpClient:   httpClient,
 		credProvider: cred,
 		userAgent:    defaultUserAgent,
+		pollInterval: defaultPollInterval,
 	}
 	for _, opt := range opts {
 		opt(c)
 	}
+	c.initServices()
+
+	return c
+}
+
+// ForLocation returns a copy of the client communicating with the API of
+// another location. All other client options are kept.
+func (c *Client) ForLocation(location string) *Client {
+	clone := *c
+	WithLocation(location)(&clone)
+	clone.initServices()
+	return &clone
+}
+
+// Location returns the location of the API the client communicates with.
+func (c *Client) Location() string {
+	return c.location
+}
 
+func (c *Client) initServices() {
 	c.common.client = c
 
 	c.ACLs = (*ACLsService)(&c.common)
+	c.Balance = (*BalanceService)(&c.common)
+	c.BurstUsage = (*BurstUsageService)(&c.common)
 	c.Capabilities = (*CapabilitiesService)(&c.common)
 	c.CloudStatus = (*CloudStatusService)(&c.common)
+	c.CurrentUsage = (*CurrentUsageService)(&c.common)
 	c.Drives = (*DrivesService)(&c.common)
 	c.FirewallPolicies = (*FirewallPoliciesService)(&c.common)
 	c.IPs = (*IPsService)(&c.common)
+	c.Jobs = (*JobsService)(&c.common)
 	c.Keypairs = (*KeypairsService)(&c.common)
 	c.Licenses = (*LicensesService)(&c.common)
+	c.Ledger = (*LedgerService)(&c.common)
 	c.LibraryDrives = (*LibraryDrivesService)(&c.common)
 	c.Locations = (*LocationsService)(&c.common)
+	c.Pricing = (*PricingService)(&c.common)
 	c.Profile = (*ProfileService)(&c.common)
 	c.Pubkeys = (*PubkeysService)(&c.common)
 	c.RemoteSnapshots = (*RemoteSnapshotsService)(&c.common)
@@ -155,9 +224,8 @@ func NewClient(cred CredentialsProvider, opts ...ClientOption) *Client {
 	c.Snapshots = (*SnapshotsService)(&c.common)
 	c.Subscriptions = (*SubscriptionsService)(&c.common)
 	c.Tags = (*TagsService)(&c.common)
+	c.Usage = (*UsageService)(&c.common)
 	c.VLANs = (*VLANsService)(&c.common)
-
-	return c
 }
 
 // NewRequest creates an API request. A relative URL can be provided in urlStr, in which case it is resolved
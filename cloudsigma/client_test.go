@@ -31,6 +31,8 @@ func setup() {
 	var opts []ClientOption
 	client = NewClient(cred, opts...)
 	client.baseURL, _ = url.Parse(fmt.Sprintf("%v/", server.URL))
+	client.endpointURL = fmt.Sprintf("%v/%%s/", server.URL)
+	client.pollInterval = time.Millisecond
 }
 
 func setupWithToken() {
@@ -41,6 +43,8 @@ func setupWithToken() {
 	var opts []ClientOption
 	client = NewClient(cred, opts...)
 	client.baseURL, _ = url.Parse(fmt.Sprintf("%v/", server.URL))
+	client.endpointURL = fmt.Sprintf("%v/%%s/", server.URL)
+	client.pollInterval = time.Millisecond
 }
 
 func teardown() {
@@ -63,6 +67,7 @@ func TestClient_Defaults(t *testing.T) {
 	assert.Equal(t, "https://zrh.cloudsigma.com/api/2.0/", client.baseURL.String())
 	assert.Contains(t, client.userAgent, "cloudsigma-sdk-go/")
 	assert.Equal(t, 0*time.Second, client.httpClient.Timeout)
+	assert.Equal(t, 5*time.Second, client.pollInterval)
 }
 
 func TestClient_WithHTTPClient(t *testing.T) {
@@ -78,6 +83,38 @@ func TestClient_WithLocation(t *testing.T) {
 	client := NewClient(nil, WithLocation("wdc"))
 
 	assert.Equal(t, expectedBaseURL, client.baseURL)
+	assert.Equal(t, "wdc", client.Location())
+}
+
+func TestClient_ForLocation(t *testing.T) {
+	httpClient := &http.Client{Timeout: 2 * time.Second}
+	client := NewClient(nil, WithHTTPClient(httpClient), WithUserAgent("custom-agent"))
+
+	fraClient := client.ForLocation("fra")
+
+	assert.Equal(t, "zrh", client.Location())
+	assert.Equal(t, "https://zrh.cloudsigma.com/api/2.0/", client.baseURL.String())
+	assert.Equal(t, "fra", fraClient.Location())
+	assert.Equal(t, "https://fra.cloudsigma.com/api/2.0/", fraClient.baseURL.String())
+	assert.Equal(t, httpClient, fraClient.httpClient)
+	assert.Equal(t, "custom-agent", fraClient.userAgent)
+	assert.Equal(t, fraClient, fraClient.Servers.client)
+}
+
+func TestClient_WithPollInterval(t *testing.T) {
+	client := NewClient(nil, WithPollInterval(10*time.Second))
+
+	assert.Equal(t, 10*time.Second, client.pollInterval)
+}
+
+func TestClient_WithPollInterval_nonPositive(t *testing.T) {
+	client := NewClient(nil, WithPollInterval(0))
+
+	assert.Equal(t, defaultPollInterval, client.pollInterval)
+
+	client = NewClient(nil, WithPollInterval(-time.Second))
+
+	assert.Equal(t, defaultPollInterval, client.pollInterval)
 }
 
 func TestClient_WithUserAgent(t *testing.T) {
@@ -0,0 +1,280 @@
+package cloudsigma
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/textproto"
+	"sort"
+	"strings"
+)
+
+// Meta keys used by the cloud-init CloudSigma datasource.
+const (
+	// MetaBase64Fields lists comma separated meta keys with base64 encoded values.
+	MetaBase64Fields = "base64_fields"
+	// MetaCloudInitUserData contains the cloud-init user data.
+	MetaCloudInitUserData = "cloudinit-user-data"
+	// MetaSSHPublicKey contains SSH public keys separated by new lines.
+	MetaSSHPublicKey = "ssh_public_key"
+
+	// DefaultMaxMetaSize is the default limit of the JSON encoded meta size
+	// checked by CloudInitConfig.BuildMeta.
+	DefaultMaxMetaSize = 64 * 1024
+
+	cloudConfigHeader      = "#cloud-config"
+	cloudConfigContentType = "text/cloud-config"
+	multipartContentType   = "multipart/mixed"
+)
+
+// ErrMetaTooLarge is returned when meta exceeds its size limit.
+var ErrMetaTooLarge = errors.New("cloudsigma-sdk-go: meta too large")
+
+// CloudInitConfig represents cloud-init data stored in server meta, and read
+// by the cloud-init CloudSigma datasource.
+type CloudInitConfig struct {
+	// CloudConfig is cloud-config YAML user data. The "#cloud-config" header
+	// is added if missing.
+	CloudConfig string
+	// Parts are additional user data parts, e.g. shell scripts. If set, the
+	// user data is a multipart MIME message with CloudConfig as its first part.
+	Parts []CloudInitPart
+	// Keypairs provide SSH public keys of the default user.
+	Keypairs []Keypair
+	// Meta is arbitrary metadata stored next to the cloud-init data.
+	Meta map[string]string
+	// MaxMetaSize limits the JSON encoded size of the built meta. Defaults
+	// to DefaultMaxMetaSize.
+	MaxMetaSize int
+}
+
+// CloudInitPart represents a part of multipart MIME user data.
+type CloudInitPart struct {
+	// ContentType of the part, e.g. "text/x-shellscript".
+	ContentType string
+	// Filename of the part, optional.
+	Filename string
+	Content  string
+}
+
+// BuildMeta returns server meta ready to be used in ServerCreateRequest. The
+// user data is base64 encoded and registered in base64_fields together with
+// keys of Meta which are not safe to be sent as plain strings.
+func (c *CloudInitConfig) BuildMeta() (map[string]interface{}, error) {
+	meta := make(map[string]interface{}, len(c.Meta)+3)
+	var base64Fields []string
+	for key, value := range c.Meta {
+		switch key {
+		case MetaBase64Fields, MetaCloudInitUserData, MetaSSHPublicKey:
+			return nil, fmt.Errorf("cloudsigma-sdk-go: meta key %q is reserved", key)
+		}
+		if needsBase64(value) {
+			value = base64.StdEncoding.EncodeToString([]byte(value))
+			base64Fields = append(base64Fields, key)
+		}
+		meta[key] = value
+	}
+
+	userData, err := c.userData()
+	if err != nil {
+		return nil, err
+	}
+	if userData != "" {
+		meta[MetaCloudInitUserData] = base64.StdEncoding.EncodeToString([]byte(userData))
+		base64Fields = append(base64Fields, MetaCloudInitUserData)
+	}
+
+	var keys []string
+	for _, k := range c.Keypairs {
+		if key := strings.TrimSpace(k.PublicKey); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) > 0 {
+		meta[MetaSSHPublicKey] = strings.Join(keys, "\n")
+	}
+
+	if len(base64Fields) > 0 {
+		sort.Strings(base64Fields)
+		meta[MetaBase64Fields] = strings.Join(base64Fields, ",")
+	}
+
+	maxSize := c.MaxMetaSize
+	if maxSize == 0 {
+		maxSize = DefaultMaxMetaSize
+	}
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxSize {
+		return nil, fmt.Errorf("%w: %d bytes exceed limit of %d bytes", ErrMetaTooLarge, len(data), maxSize)
+	}
+
+	return meta, nil
+}
+
+func (c *CloudInitConfig) userData() (string, error) {
+	cloudConfig := c.CloudConfig
+	if cloudConfig != "" && !strings.HasPrefix(cloudConfig, cloudConfigHeader) {
+		cloudConfig = cloudConfigHeader + "\n" + cloudConfig
+	}
+	if len(c.Parts) == 0 {
+		return cloudConfig, nil
+	}
+
+	parts := c.Parts
+	if cloudConfig != "" {
+		parts = append([]CloudInitPart{{ContentType: cloudConfigContentType, Content: cloudConfig}}, parts...)
+	}
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	for _, p := range parts {
+		if p.ContentType == "" {
+			return "", fmt.Errorf("cloudsigma-sdk-go: content type of user data part %q cannot be empty", p.Filename)
+		}
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Type", p.ContentType+`; charset="utf-8"`)
+		header.Set("MIME-Version", "1.0")
+		if p.Filename != "" {
+			header.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": p.Filename}))
+		}
+		pw, err := w.CreatePart(header)
+		if err != nil {
+			return "", err
+		}
+		if _, err := io.WriteString(pw, p.Content); err != nil {
+			return "", err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+
+	contentType := mime.FormatMediaType(multipartContentType, map[string]string{"boundary": w.Boundary()})
+	return "Content-Type: " + contentType + "\nMIME-Version: 1.0\n\n" + body.String(), nil
+}
+
+// ParseCloudInit reads cloud-init data back from the meta of a server.
+// Multipart user data is split into parts, and its first cloud-config part is
+// returned as CloudConfig. Meta keys other than the cloud-init ones are
+// returned in Meta as strings.
+func ParseCloudInit(server *Server) (*CloudInitConfig, error) {
+	if server == nil {
+		return nil, ErrEmptyArgument
+	}
+
+	meta, err := DecodeBase64Meta(server.Meta)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &CloudInitConfig{}
+	for key, value := range meta {
+		switch key {
+		case MetaBase64Fields:
+		case MetaCloudInitUserData:
+			if err := config.parseUserData(fmt.Sprint(value)); err != nil {
+				return nil, err
+			}
+		case MetaSSHPublicKey:
+			for _, key := range strings.Split(fmt.Sprint(value), "\n") {
+				if key = strings.TrimSpace(key); key != "" {
+					config.Keypairs = append(config.Keypairs, Keypair{PublicKey: key})
+				}
+			}
+		default:
+			if config.Meta == nil {
+				config.Meta = make(map[string]string)
+			}
+			config.Meta[key] = fmt.Sprint(value)
+		}
+	}
+
+	return config, nil
+}
+
+func (c *CloudInitConfig) parseUserData(userData string) error {
+	msg, err := mail.ReadMessage(strings.NewReader(userData))
+	if err != nil {
+		c.CloudConfig = userData
+		return nil
+	}
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != multipartContentType {
+		c.CloudConfig = userData
+		return nil
+	}
+
+	r := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		p, err := r.NextPart()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("cloudsigma-sdk-go: cannot parse multipart user data: %w", err)
+		}
+		content, err := io.ReadAll(p)
+		if err != nil {
+			return err
+		}
+
+		contentType, _, _ := mime.ParseMediaType(p.Header.Get("Content-Type"))
+		if contentType == cloudConfigContentType && c.CloudConfig == "" {
+			c.CloudConfig = string(content)
+			continue
+		}
+		c.Parts = append(c.Parts, CloudInitPart{
+			ContentType: contentType,
+			Filename:    p.FileName(),
+			Content:     string(content),
+		})
+	}
+}
+
+// DecodeBase64Meta returns a copy of meta with the values listed in
+// base64_fields decoded. Listed keys missing from meta are ignored.
+func DecodeBase64Meta(meta map[string]interface{}) (map[string]interface{}, error) {
+	if meta == nil {
+		return nil, nil
+	}
+	decoded := make(map[string]interface{}, len(meta))
+	for key, value := range meta {
+		decoded[key] = value
+	}
+
+	fields, _ := meta[MetaBase64Fields].(string)
+	for _, key := range strings.Split(fields, ",") {
+		key = strings.TrimSpace(key)
+		value, ok := meta[key].(string)
+		if key == "" || !ok {
+			continue
+		}
+		data, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, fmt.Errorf("cloudsigma-sdk-go: cannot decode base64 meta %q: %w", key, err)
+		}
+		decoded[key] = string(data)
+	}
+	return decoded, nil
+}
+
+// needsBase64 reports whether a meta value contains characters which are not
+// safe to be stored as plain meta string, i.e. control characters including
+// new lines.
+func needsBase64(value string) bool {
+	for _, r := range value {
+		if r < 0x20 || r == 0x7f {
+			return true
+		}
+	}
+	return false
+}
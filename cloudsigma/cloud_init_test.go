@@ -0,0 +1,139 @@
+package cloudsigma
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCloudInit_BuildMeta(t *testing.T) {
+	config := &CloudInitConfig{
+		CloudConfig: "packages:\n  - nginx\n",
+		Keypairs: []Keypair{
+			{PublicKey: "ssh-ed25519 AAAA first\n"},
+			{Name: "without public key"},
+			{PublicKey: "ssh-rsa BBBB second"},
+		},
+		Meta: map[string]string{
+			"role":  "web",
+			"motd":  "hello\nworld",
+			"empty": "",
+		},
+	}
+
+	meta, err := config.BuildMeta()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "cloudinit-user-data,motd", meta[MetaBase64Fields])
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("#cloud-config\npackages:\n  - nginx\n")), meta[MetaCloudInitUserData])
+	assert.Equal(t, "ssh-ed25519 AAAA first\nssh-rsa BBBB second", meta[MetaSSHPublicKey])
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("hello\nworld")), meta["motd"])
+	assert.Equal(t, "web", meta["role"])
+	assert.Equal(t, "", meta["empty"])
+}
+
+func TestCloudInit_BuildMeta_reservedKey(t *testing.T) {
+	config := &CloudInitConfig{Meta: map[string]string{MetaBase64Fields: "role"}}
+
+	_, err := config.BuildMeta()
+
+	assert.EqualError(t, err, `cloudsigma-sdk-go: meta key "base64_fields" is reserved`)
+}
+
+func TestCloudInit_BuildMeta_tooLarge(t *testing.T) {
+	config := &CloudInitConfig{
+		CloudConfig: strings.Repeat("# padding\n", 100),
+		MaxMetaSize: 512,
+	}
+
+	_, err := config.BuildMeta()
+
+	assert.ErrorIs(t, err, ErrMetaTooLarge)
+}
+
+func TestCloudInit_BuildMeta_multipartPartWithoutContentType(t *testing.T) {
+	config := &CloudInitConfig{Parts: []CloudInitPart{{Content: "echo"}}}
+
+	_, err := config.BuildMeta()
+
+	assert.Error(t, err)
+}
+
+func TestCloudInit_roundTrip(t *testing.T) {
+	config := &CloudInitConfig{
+		CloudConfig: "#cloud-config\nruncmd:\n  - [touch, /tmp/ok]\n",
+		Parts: []CloudInitPart{
+			{ContentType: "text/x-shellscript", Filename: "setup.sh", Content: "#!/bin/sh\necho setup\n"},
+			{ContentType: "text/x-include-url", Content: "https://example.com/user-data"},
+		},
+		Keypairs: []Keypair{{PublicKey: "ssh-ed25519 AAAA"}},
+		Meta:     map[string]string{"role": "web", "motd": "multi\nline"},
+	}
+	meta, err := config.BuildMeta()
+	assert.NoError(t, err)
+
+	parsed, err := ParseCloudInit(&Server{Meta: meta})
+
+	assert.NoError(t, err)
+	assert.Equal(t, config, parsed)
+}
+
+func TestCloudInit_ParseCloudInit_plainUserData(t *testing.T) {
+	server := &Server{
+		Meta: map[string]interface{}{
+			MetaCloudInitUserData: "#cloud-config\nhostname: web\n",
+			"replicas":            float64(3),
+		},
+	}
+	expected := &CloudInitConfig{
+		CloudConfig: "#cloud-config\nhostname: web\n",
+		Meta:        map[string]string{"replicas": "3"},
+	}
+
+	config, err := ParseCloudInit(server)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, config)
+	assert.Equal(t, "#cloud-config\nhostname: web\n", server.Meta[MetaCloudInitUserData])
+}
+
+func TestCloudInit_ParseCloudInit_invalidBase64(t *testing.T) {
+	server := &Server{
+		Meta: map[string]interface{}{
+			MetaBase64Fields:      MetaCloudInitUserData,
+			MetaCloudInitUserData: "not base64!",
+		},
+	}
+
+	_, err := ParseCloudInit(server)
+
+	assert.Error(t, err)
+}
+
+func TestCloudInit_ParseCloudInit_emptyServer(t *testing.T) {
+	_, err := ParseCloudInit(nil)
+
+	assert.Error(t, err)
+	assert.Equal(t, ErrEmptyArgument.Error(), err.Error())
+}
+
+func TestCloudInit_DecodeBase64Meta(t *testing.T) {
+	meta := map[string]interface{}{
+		MetaBase64Fields: "a, b,missing",
+		"a":              "YQ==",
+		"b":              "Yg==",
+		"c":              "Yw==",
+	}
+
+	decoded, err := DecodeBase64Meta(meta)
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{MetaBase64Fields: "a, b,missing", "a": "a", "b": "b", "c": "Yw=="}, decoded)
+	assert.Equal(t, "YQ==", meta["a"])
+
+	_, err = DecodeBase64Meta(map[string]interface{}{MetaBase64Fields: "a", "a": "not base64!"})
+
+	assert.EqualError(t, err, `cloudsigma-sdk-go: cannot decode base64 meta "a": illegal base64 data at input byte 3`)
+}
@@ -0,0 +1,222 @@
+package cloudsigma
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+const (
+	secondsPerHour  = 60 * 60
+	secondsPerMonth = 30 * 24 * secondsPerHour
+
+	// Price resources which are not named after a server or drive field.
+	resourceCPU      = "cpu"
+	resourceMemory   = "mem"
+	resourceDSSD     = "dssd"
+	resourceMagnetic = "msd"
+	resourceIP       = "ip"
+	resourceVLAN     = "vlan"
+)
+
+// CostEstimator estimates costs of CloudSigma resources from a price list.
+type CostEstimator struct {
+	// Pricing is the price list used for the estimates.
+	Pricing *Pricing
+	// Currency of the estimates, usually the currency of the user profile.
+	Currency string
+	// FreeTier is subtracted from the estimated memory and DSSD storage.
+	FreeTier *CloudStatusFreeTier
+}
+
+// EstimateInput represents the resources to estimate costs for. Drives
+// attached to Servers are included if they have a size, or if they are
+// listed in Drives. Every drive is counted once.
+type EstimateInput struct {
+	Servers []Server
+	Drives  []Drive
+	IPs     []IP
+	VLANs   []VLAN
+}
+
+// Cost represents an hourly and a monthly (30 days) cost.
+type Cost struct {
+	Hourly  Decimal
+	Monthly Decimal
+}
+
+// Add returns the sum of c and other.
+func (c Cost) Add(other Cost) Cost {
+	return Cost{Hourly: c.Hourly.Add(other.Hourly), Monthly: c.Monthly.Add(other.Monthly)}
+}
+
+// EstimateItem represents the estimated cost of a single price resource.
+// Quantity is in base units, i.e. MHz for CPU, bytes for memory and storage,
+// and items for IPs, VLANs and licenses.
+type EstimateItem struct {
+	Resource     string
+	Quantity     int64
+	Burst        Cost
+	Subscription Cost
+}
+
+// Estimate represents estimated costs of resources, if paid at burst rates
+// or covered by subscriptions at the current price levels.
+type Estimate struct {
+	Currency     string
+	Items        []EstimateItem
+	Burst        Cost
+	Subscription Cost
+}
+
+// NewCostEstimator returns a CostEstimator using the current price list, the
+// currency of the user profile and the free tier of the cloud.
+func NewCostEstimator(ctx context.Context, client *Client) (*CostEstimator, error) {
+	if client == nil {
+		return nil, ErrEmptyArgument
+	}
+
+	profile, _, err := client.Profile.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pricing, _, err := client.Pricing.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cloudStatus, _, err := client.CloudStatus.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CostEstimator{
+		Pricing:  pricing,
+		Currency: profile.Currency,
+		FreeTier: cloudStatus.FreeTier,
+	}, nil
+}
+
+// EstimateServerCreate estimates the costs of servers in a create request.
+// drives provide sizes and licenses of the attached drives.
+func (e *CostEstimator) EstimateServerCreate(createRequest *ServerCreateRequest, drives []Drive) (*Estimate, error) {
+	if createRequest == nil {
+		return nil, ErrEmptyPayloadNotAllowed
+	}
+
+	var attached []Drive
+	byUUID := make(map[string]Drive, len(drives))
+	for _, d := range drives {
+		byUUID[d.UUID] = d
+	}
+	for _, server := range createRequest.Servers {
+		for _, sd := range server.Drives {
+			if sd.Drive == nil {
+				continue
+			}
+			if d, ok := byUUID[sd.Drive.UUID]; ok {
+				attached = append(attached, d)
+			}
+		}
+	}
+
+	return e.Estimate(&EstimateInput{Servers: createRequest.Servers, Drives: attached})
+}
+
+// Estimate estimates the costs of the given resources. An error wrapping
+// ErrResourceNotFound is returned if a resource has no price in the
+// estimator currency.
+func (e *CostEstimator) Estimate(input *EstimateInput) (*Estimate, error) {
+	if input == nil {
+		return nil, ErrEmptyPayloadNotAllowed
+	}
+	if e.Pricing == nil || e.Currency == "" {
+		return nil, ErrEmptyArgument
+	}
+
+	quantities := make(map[string]int64)
+	drives := make(map[string]bool)
+	addDrive := func(d *Drive) {
+		if d == nil || d.Size == 0 || drives[d.UUID] {
+			return
+		}
+		if d.UUID != "" {
+			drives[d.UUID] = true
+		}
+		quantities[storageResource(d)] += int64(d.Size)
+		for _, l := range d.Licenses {
+			if l.License != nil && l.License.Name != "" {
+				quantities[l.License.Name] += int64(l.Amount)
+			}
+		}
+	}
+
+	for i := range input.Drives {
+		addDrive(&input.Drives[i])
+	}
+	for _, server := range input.Servers {
+		quantities[resourceCPU] += int64(server.CPU)
+		quantities[resourceMemory] += int64(server.Memory)
+		for _, sd := range server.Drives {
+			addDrive(sd.Drive)
+		}
+	}
+	quantities[resourceIP] += int64(len(input.IPs))
+	quantities[resourceVLAN] += int64(len(input.VLANs))
+
+	if e.FreeTier != nil {
+		quantities[resourceMemory] = max(0, quantities[resourceMemory]-int64(e.FreeTier.Memory))
+		quantities[resourceDSSD] = max(0, quantities[resourceDSSD]-int64(e.FreeTier.DSSD))
+	}
+
+	resources := make([]string, 0, len(quantities))
+	for resource, quantity := range quantities {
+		if quantity > 0 {
+			resources = append(resources, resource)
+		}
+	}
+	sort.Strings(resources)
+
+	estimate := &Estimate{Currency: e.Currency}
+	for _, resource := range resources {
+		quantity := quantities[resource]
+		burst, ok := e.Pricing.BurstPrice(resource, e.Currency)
+		if !ok {
+			return nil, fmt.Errorf("%w: no burst price of %q in %v", ErrResourceNotFound, resource, e.Currency)
+		}
+		subscription, ok := e.Pricing.CurrentPrice(resource, e.Currency)
+		if !ok {
+			return nil, fmt.Errorf("%w: no subscription price of %q in %v", ErrResourceNotFound, resource, e.Currency)
+		}
+
+		item := EstimateItem{
+			Resource:     resource,
+			Quantity:     quantity,
+			Burst:        priceCost(burst, quantity),
+			Subscription: priceCost(subscription, quantity),
+		}
+		estimate.Items = append(estimate.Items, item)
+		estimate.Burst = estimate.Burst.Add(item.Burst)
+		estimate.Subscription = estimate.Subscription.Add(item.Subscription)
+	}
+
+	return estimate, nil
+}
+
+func priceCost(price *Price, quantity int64) Cost {
+	return Cost{
+		Hourly:  price.Cost(quantity, secondsPerHour),
+		Monthly: price.Cost(quantity, secondsPerMonth),
+	}
+}
+
+// storageResource returns the price resource of the drive storage type.
+func storageResource(d *Drive) string {
+	storageType := d.StorageType
+	if storageType == "" && d.Runtime != nil {
+		storageType = d.Runtime.StorageType
+	}
+	if storageType == StorageTypeMagnetic {
+		return resourceMagnetic
+	}
+	return resourceDSSD
+}
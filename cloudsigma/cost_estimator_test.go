@@ -0,0 +1,118 @@
+package cloudsigma
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func assertDecimal(t *testing.T, expected string, actual Decimal) {
+	t.Helper()
+	assert.Equal(t, 0, MustParseDecimal(expected).Cmp(actual), "expected %v, got %v", expected, actual)
+}
+
+func setupCostEstimator() {
+	setup()
+
+	mux.HandleFunc("/pricing/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, pricingJSON)
+	})
+	mux.HandleFunc("/profile/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"currency":"USD","uuid":"user-uuid"}`)
+	})
+	mux.HandleFunc("/cloud_status/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, `{"free_tier":{"dssd":%d,"mem":%d}}`, 5*gibibyte, gibibyte)
+	})
+}
+
+func TestNewCostEstimator(t *testing.T) {
+	setupCostEstimator()
+	defer teardown()
+
+	estimator, err := NewCostEstimator(ctx, client)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "USD", estimator.Currency)
+	assert.Equal(t, &CloudStatusFreeTier{DSSD: 5 * gibibyte, Memory: gibibyte}, estimator.FreeTier)
+	assert.Len(t, estimator.Pricing.Prices, 13)
+}
+
+func TestCostEstimator_EstimateServerCreate(t *testing.T) {
+	setupCostEstimator()
+	defer teardown()
+
+	estimator, _ := NewCostEstimator(ctx, client)
+	createRequest := &ServerCreateRequest{
+		Servers: []Server{
+			{
+				CPU:    2000,
+				Memory: 2 * gibibyte,
+				Drives: []ServerDrive{{Drive: &Drive{UUID: "disk-uuid"}}, {Drive: &Drive{UUID: "unknown-uuid"}}},
+			},
+		},
+	}
+	drives := []Drive{
+		{
+			UUID:        "disk-uuid",
+			Size:        15 * gibibyte,
+			StorageType: "dssd",
+			Licenses:    []DriveLicense{{Amount: 1, License: &License{Name: "msft_lwa_00135"}}},
+		},
+		{UUID: "other-uuid", Size: 100 * gibibyte},
+	}
+
+	estimate, err := estimator.EstimateServerCreate(createRequest, drives)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "USD", estimate.Currency)
+	var resources []string
+	for _, item := range estimate.Items {
+		resources = append(resources, item.Resource)
+	}
+	assert.Equal(t, []string{"cpu", "dssd", "mem", "msft_lwa_00135"}, resources)
+	// dssd and mem are reduced by the free tier to 10 GB and 1 GB
+	assert.Equal(t, int64(10*gibibyte), estimate.Items[1].Quantity)
+	assertDecimal(t, "1", estimate.Items[1].Subscription.Monthly)
+	// 2 GHz * 10 + 1 GB * 7.5 + 10 GB * 0.1 + 20
+	assertDecimal(t, "48.5", estimate.Subscription.Monthly)
+	// 2 GHz * 20 + 1 GB * 15 + 10 GB * 0.2 + 20
+	assertDecimal(t, "77", estimate.Burst.Monthly)
+	assertDecimal(t, "0.0555555556", estimate.Items[0].Burst.Hourly)
+}
+
+func TestCostEstimator_Estimate(t *testing.T) {
+	setupCostEstimator()
+	defer teardown()
+
+	pricing, _, _ := client.Pricing.Get(ctx)
+	estimator := &CostEstimator{Pricing: pricing, Currency: "USD"}
+
+	estimate, err := estimator.Estimate(&EstimateInput{
+		Drives: []Drive{{Size: 100 * gibibyte, StorageType: "magnetic"}, {Size: 100 * gibibyte, Runtime: &DriveRuntime{StorageType: "magnetic"}}},
+		IPs:    []IP{{UUID: "1.2.3.4"}, {UUID: "1.2.3.5"}},
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, estimate.Items, 2)
+	assert.Equal(t, "ip", estimate.Items[0].Resource)
+	assert.Equal(t, "msd", estimate.Items[1].Resource)
+	// 2 IPs * 4 + 200 GB * 0.04
+	assertDecimal(t, "16", estimate.Subscription.Monthly)
+}
+
+func TestCostEstimator_Estimate_missingPrice(t *testing.T) {
+	estimator := &CostEstimator{Pricing: &Pricing{}, Currency: "USD"}
+
+	_, err := estimator.Estimate(&EstimateInput{VLANs: []VLAN{{UUID: "vlan-uuid"}}})
+
+	assert.True(t, errors.Is(err, ErrResourceNotFound))
+}
+
+func TestCostEstimator_Estimate_emptyPayload(t *testing.T) {
+	_, err := (&CostEstimator{}).Estimate(nil)
+
+	assert.Equal(t, ErrEmptyPayloadNotAllowed, err)
+}
@@ -0,0 +1,51 @@
+package cloudsigma
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+const currentUsageBasePath = "currentusage"
+
+// CurrentUsageService handles communication with the current usage related
+// methods of the CloudSigma API.
+//
+// CloudSigma API docs: https://cloudsigma-docs.readthedocs.io/en/latest/billing.html#current-usage
+type CurrentUsageService service
+
+// CurrentUsage represents the balance and the resource usage of the user at
+// the moment.
+type CurrentUsage struct {
+	Balance *Balance                 `json:"balance,omitempty"`
+	Usage   map[string]ResourceUsage `json:"usage,omitempty"`
+}
+
+// ResourceUsage represents the usage of a single resource type, e.g. "cpu"
+// in MHz or "mem" in bytes. Usage over the subscribed amount is billed as
+// burst.
+type ResourceUsage struct {
+	Burst      int64 `json:"burst"`
+	Subscribed int64 `json:"subscribed"`
+	Using      int64 `json:"using"`
+}
+
+// Get provides the current balance and resource usage of the user.
+//
+// CloudSigma API docs: https://cloudsigma-docs.readthedocs.io/en/latest/billing.html#current-usage
+func (s *CurrentUsageService) Get(ctx context.Context) (*CurrentUsage, *Response, error) {
+	path := fmt.Sprintf("%v/", currentUsageBasePath)
+
+	req, err := s.client.NewRequest(http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	currentUsage := new(CurrentUsage)
+	resp, err := s.client.Do(ctx, req, currentUsage)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return currentUsage, resp, nil
+}
@@ -0,0 +1,30 @@
+package cloudsigma
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCurrentUsage_Get(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/currentusage/", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		_, _ = fmt.Fprint(w, `{"balance":{"balance":"10.50","currency":"USD"},"usage":{"cpu":{"burst":500,"subscribed":2000,"using":2500}}}`)
+	})
+	expected := &CurrentUsage{
+		Balance: &Balance{Balance: MustParseDecimal("10.50"), Currency: "USD"},
+		Usage: map[string]ResourceUsage{
+			"cpu": {Burst: 500, Subscribed: 2000, Using: 2500},
+		},
+	}
+
+	currentUsage, _, err := client.CurrentUsage.Get(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, currentUsage)
+}
@@ -0,0 +1,224 @@
+package cloudsigma
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Decimal represents an exact decimal number, e.g. a monetary amount. The
+// zero value is 0. Decimal values are immutable, and all methods return new
+// values.
+//
+// The CloudSigma API returns amounts as JSON strings like "12.34000".
+// Decimal is decoded from JSON strings and numbers, and encoded as a string
+// keeping the number of fractional digits.
+type Decimal struct {
+	value *big.Int // unscaled value, nil for zero
+	scale int      // number of fractional digits
+}
+
+// NewDecimal returns a Decimal equal to value * 10^-scale.
+func NewDecimal(value int64, scale int) Decimal {
+	if scale < 0 {
+		value *= pow10(-scale).Int64()
+		scale = 0
+	}
+	return newDecimal(big.NewInt(value), scale)
+}
+
+// ParseDecimal parses a decimal number like "-12.340".
+func ParseDecimal(s string) (Decimal, error) {
+	s = strings.TrimSpace(s)
+	digits := s
+	if strings.HasPrefix(digits, "-") || strings.HasPrefix(digits, "+") {
+		digits = digits[1:]
+	}
+	integer, fraction, _ := strings.Cut(digits, ".")
+	if integer == "" && fraction == "" || !isDigits(integer) || !isDigits(fraction) {
+		return Decimal{}, fmt.Errorf("cloudsigma-sdk-go: cannot parse decimal %q", s)
+	}
+
+	value, ok := new(big.Int).SetString(integer+fraction, 10)
+	if !ok {
+		return Decimal{}, fmt.Errorf("cloudsigma-sdk-go: cannot parse decimal %q", s)
+	}
+	if strings.HasPrefix(s, "-") {
+		value.Neg(value)
+	}
+	return newDecimal(value, len(fraction)), nil
+}
+
+// MustParseDecimal is like ParseDecimal but panics if s cannot be parsed.
+// It simplifies initialization of constant amounts.
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func newDecimal(value *big.Int, scale int) Decimal {
+	if value.Sign() == 0 {
+		return Decimal{scale: scale}
+	}
+	return Decimal{value: value, scale: scale}
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// unscaled returns the unscaled value of d at the given scale, which must
+// not be lower than the scale of d.
+func (d Decimal) unscaled(scale int) *big.Int {
+	if d.value == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Mul(d.value, pow10(scale-d.scale))
+}
+
+// Add returns d + other.
+func (d Decimal) Add(other Decimal) Decimal {
+	scale := max(d.scale, other.scale)
+	return newDecimal(new(big.Int).Add(d.unscaled(scale), other.unscaled(scale)), scale)
+}
+
+// Sub returns d - other.
+func (d Decimal) Sub(other Decimal) Decimal {
+	scale := max(d.scale, other.scale)
+	return newDecimal(new(big.Int).Sub(d.unscaled(scale), other.unscaled(scale)), scale)
+}
+
+// Mul returns d * other.
+func (d Decimal) Mul(other Decimal) Decimal {
+	return newDecimal(new(big.Int).Mul(d.unscaled(d.scale), other.unscaled(other.scale)), d.scale+other.scale)
+}
+
+// Div returns d / other rounded half away from zero to the given number of
+// fractional digits. It panics if other is zero.
+func (d Decimal) Div(other Decimal, places int) Decimal {
+	if other.IsZero() {
+		panic("cloudsigma-sdk-go: decimal division by zero")
+	}
+	// d.value * 10^(places + other.scale - d.scale) / other.value, computed
+	// with one extra digit for rounding.
+	numerator := d.unscaled(d.scale)
+	denominator := other.unscaled(other.scale)
+	shift := places + other.scale - d.scale + 1
+	if shift >= 0 {
+		numerator.Mul(numerator, pow10(shift))
+	} else {
+		denominator.Mul(denominator, pow10(-shift))
+	}
+	return newDecimal(numerator.Quo(numerator, denominator), places+1).Round(places)
+}
+
+// Neg returns -d.
+func (d Decimal) Neg() Decimal {
+	return newDecimal(new(big.Int).Neg(d.unscaled(d.scale)), d.scale)
+}
+
+// Round returns d rounded half away from zero to the given number of
+// fractional digits.
+func (d Decimal) Round(places int) Decimal {
+	if places >= d.scale {
+		return newDecimal(d.unscaled(places), places)
+	}
+	divisor := pow10(d.scale - places)
+	quotient, remainder := new(big.Int).QuoRem(d.unscaled(d.scale), divisor, new(big.Int))
+	if remainder.Abs(remainder).Mul(remainder, big.NewInt(2)).Cmp(divisor) >= 0 {
+		quotient.Add(quotient, big.NewInt(int64(d.Sign())))
+	}
+	return newDecimal(quotient, places)
+}
+
+// Cmp compares d and other and returns -1, 0 or +1.
+func (d Decimal) Cmp(other Decimal) int {
+	scale := max(d.scale, other.scale)
+	return d.unscaled(scale).Cmp(other.unscaled(scale))
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of d.
+func (d Decimal) Sign() int {
+	if d.value == nil {
+		return 0
+	}
+	return d.value.Sign()
+}
+
+// IsZero reports whether d is zero.
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// Int64 returns the integer part of d. The result is undefined if it does
+// not fit into int64.
+func (d Decimal) Int64() int64 {
+	return new(big.Int).Quo(d.unscaled(d.scale), pow10(d.scale)).Int64()
+}
+
+// Float64 returns the nearest float64 value of d.
+func (d Decimal) Float64() float64 {
+	f, _ := new(big.Rat).SetFrac(d.unscaled(d.scale), pow10(d.scale)).Float64()
+	return f
+}
+
+// String returns d with all its fractional digits, e.g. "12.340".
+func (d Decimal) String() string {
+	digits := d.unscaled(d.scale).String()
+	sign := ""
+	if strings.HasPrefix(digits, "-") {
+		sign, digits = "-", digits[1:]
+	}
+	if d.scale == 0 {
+		return sign + digits
+	}
+	if len(digits) <= d.scale {
+		digits = strings.Repeat("0", d.scale-len(digits)+1) + digits
+	}
+	return sign + digits[:len(digits)-d.scale] + "." + digits[len(digits)-d.scale:]
+}
+
+// MarshalJSON encodes d as a JSON string.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON decodes d from a JSON string or number. null and empty
+// strings are decoded as zero.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	data = bytes.Trim(data, `"`)
+	if len(data) == 0 || string(data) == "null" {
+		*d = Decimal{}
+		return nil
+	}
+
+	s := string(data)
+	if strings.ContainsAny(s, "eE") {
+		r, ok := new(big.Rat).SetString(s)
+		if !ok {
+			return fmt.Errorf("cloudsigma-sdk-go: cannot parse decimal %q", s)
+		}
+		s = r.FloatString(18)
+	}
+
+	parsed, err := ParseDecimal(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
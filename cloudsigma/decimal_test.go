@@ -0,0 +1,83 @@
+package cloudsigma
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDecimal(t *testing.T) {
+	tests := map[string]string{
+		"12.340":   "12.340",
+		"-0.00030": "-0.00030",
+		"+7":       "7",
+		".5":       "0.5",
+		"3.":       "3",
+		"0":        "0",
+	}
+	for input, expected := range tests {
+		d, err := ParseDecimal(input)
+
+		assert.NoError(t, err, input)
+		assert.Equal(t, expected, d.String(), input)
+	}
+}
+
+func TestParseDecimal_invalid(t *testing.T) {
+	for _, input := range []string{"", ".", "1.2.3", "abc", "1e3", "--1"} {
+		_, err := ParseDecimal(input)
+
+		assert.Error(t, err, input)
+	}
+}
+
+func TestDecimal_arithmetic(t *testing.T) {
+	a := MustParseDecimal("10.05")
+	b := MustParseDecimal("0.1")
+
+	assert.Equal(t, "10.15", a.Add(b).String())
+	assert.Equal(t, "9.95", a.Sub(b).String())
+	assert.Equal(t, "1.005", a.Mul(b).String())
+	assert.Equal(t, "-10.05", a.Neg().String())
+	assert.Equal(t, "100.50", a.Div(b, 2).String())
+	assert.Equal(t, "3.35", a.Div(NewDecimal(3, 0), 2).String())
+	assert.Equal(t, "-0.33", NewDecimal(-1, 0).Div(NewDecimal(3, 0), 2).String())
+	assert.Equal(t, 1, a.Cmp(b))
+	assert.Equal(t, 0, MustParseDecimal("0.10").Cmp(b))
+	assert.True(t, a.Sub(a).IsZero())
+	assert.Equal(t, 10.05, a.Float64())
+	assert.Equal(t, int64(10), a.Int64())
+	assert.Equal(t, int64(-10), a.Neg().Int64())
+}
+
+func TestDecimal_Round(t *testing.T) {
+	assert.Equal(t, "1.01", MustParseDecimal("1.005").Round(2).String())
+	assert.Equal(t, "-1.01", MustParseDecimal("-1.005").Round(2).String())
+	assert.Equal(t, "1.00", MustParseDecimal("1.004").Round(2).String())
+	assert.Equal(t, "2.500", MustParseDecimal("2.5").Round(3).String())
+	assert.Equal(t, "0", Decimal{}.Round(0).String())
+}
+
+func TestDecimal_JSON(t *testing.T) {
+	var v struct {
+		String Decimal `json:"string"`
+		Number Decimal `json:"number"`
+		Exp    Decimal `json:"exp"`
+		Null   Decimal `json:"null"`
+		Empty  Decimal `json:"empty"`
+	}
+
+	err := json.Unmarshal([]byte(`{"string":"0.01032","number":12.5,"exp":1e-3,"null":null,"empty":""}`), &v)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "0.01032", v.String.String())
+	assert.Equal(t, "12.5", v.Number.String())
+	assert.Equal(t, 0, v.Exp.Cmp(MustParseDecimal("0.001")))
+	assert.True(t, v.Null.IsZero())
+	assert.True(t, v.Empty.IsZero())
+
+	data, err := json.Marshal(v.String)
+	assert.NoError(t, err)
+	assert.Equal(t, `"0.01032"`, string(data))
+}
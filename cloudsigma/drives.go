@@ -1,12 +1,26 @@
 package cloudsigma
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"strings"
 )
 
-const drivesBasePath = "drives"
+const (
+	drivesBasePath = "drives"
+
+	// maxBulkUUIDsLength is the maximum length of comma separated uuids sent
+	// in a single bulk request, which keeps request URLs well below the
+	// common 2048 characters limit.
+	maxBulkUUIDsLength = 1500
+
+	// directUploadHostPrefix is the host prefix of the API used for uploads.
+	directUploadHostPrefix = "direct."
+)
 
 // DrivesService handles communication with the drives related methods of
 // the CloudSigma API.
@@ -17,8 +31,9 @@ type DrivesService service
 // Drive represents a CloudSigma drive.
 type Drive struct {
 	AllowMultimount bool                   `json:"allow_multimount,omitempty"`
+	Jobs            []ResourceLink         `json:"jobs,omitempty"`
 	Licenses        []DriveLicense         `json:"licenses,omitempty"`
-	Media           string                 `json:"media,omitempty"`
+	Media           DriveMedia             `json:"media,omitempty"`
 	Meta            map[string]interface{} `json:"meta,omitempty"`
 	MountedOn       []ResourceLink         `json:"mounted_on,omitempty"`
 	Name            string                 `json:"name,omitempty"`
@@ -28,17 +43,105 @@ type Drive struct {
 	Runtime         *DriveRuntime          `json:"runtime,omitempty"`
 	Size            int                    `json:"size,omitempty"`
 	Snapshots       []ResourceLink         `json:"snapshots,omitempty"`
-	Status          string                 `json:"status,omitempty"`
-	StorageType     string                 `json:"storage_type,omitempty"`
+	Status          DriveStatus            `json:"status,omitempty"`
+	StorageType     StorageType            `json:"storage_type,omitempty"`
 	Tags            []Tag                  `json:"tags,omitempty"`
 	UUID            string                 `json:"uuid,omitempty"`
+
+	// Extra holds API fields unknown to the SDK, see Server.Extra.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// MarshalJSON is a custom marshaller for Drive. It adds the fields of Extra.
+func (d Drive) MarshalJSON() ([]byte, error) {
+	type Alias Drive
+	return marshalWithExtra(Alias(d), d.Extra)
+}
+
+// UnmarshalJSON is a custom unmarshaller for Drive. It keeps the fields
+// unknown to the SDK in Extra.
+func (d *Drive) UnmarshalJSON(data []byte) error {
+	type Alias Drive
+	extra, err := unmarshalWithExtra(data, (*Alias)(d))
+	if err != nil {
+		return err
+	}
+	d.Extra = extra
+	return nil
+}
+
+// DriveStatus represents the status of a drive. Statuses unknown to the SDK
+// are kept as they are.
+type DriveStatus string
+
+// Drive statuses.
+const (
+	DriveStatusCloningDestination DriveStatus = "cloning_dst"
+	DriveStatusCloningSource      DriveStatus = "cloning_src"
+	DriveStatusCopying            DriveStatus = "copying"
+	DriveStatusCreating           DriveStatus = "creating"
+	DriveStatusMounted            DriveStatus = "mounted"
+	DriveStatusResizing           DriveStatus = "resizing"
+	DriveStatusUnavailable        DriveStatus = "unavailable"
+	DriveStatusUnmounted          DriveStatus = "unmounted"
+	DriveStatusUploading          DriveStatus = "uploading"
+)
+
+// IsValid reports whether the status is known to the SDK.
+func (s DriveStatus) IsValid() bool {
+	switch s {
+	case DriveStatusCloningDestination, DriveStatusCloningSource, DriveStatusCopying,
+		DriveStatusCreating, DriveStatusMounted, DriveStatusResizing,
+		DriveStatusUnavailable, DriveStatusUnmounted, DriveStatusUploading:
+		return true
+	}
+	return false
+}
+
+// IsTransitional reports whether an operation on the drive is in progress,
+// i.e. the drive is being created, cloned, copied, resized or uploaded.
+func (s DriveStatus) IsTransitional() bool {
+	switch s {
+	case DriveStatusCloningDestination, DriveStatusCloningSource, DriveStatusCopying,
+		DriveStatusCreating, DriveStatusResizing, DriveStatusUploading:
+		return true
+	}
+	return false
+}
+
+// DriveMedia represents the media type of a drive.
+type DriveMedia string
+
+// Drive media types.
+const (
+	DriveMediaCDROM DriveMedia = "cdrom"
+	DriveMediaDisk  DriveMedia = "disk"
+)
+
+// IsValid reports whether the media type is known to the SDK.
+func (m DriveMedia) IsValid() bool {
+	return m == DriveMediaCDROM || m == DriveMediaDisk
+}
+
+// StorageType represents the storage type of a drive.
+type StorageType string
+
+// Storage types.
+const (
+	StorageTypeDSSD     StorageType = "dssd"
+	StorageTypeMagnetic StorageType = "magnetic"
+)
+
+// IsValid reports whether the storage type is known to the SDK.
+func (t StorageType) IsValid() bool {
+	return t == StorageTypeDSSD || t == StorageTypeMagnetic
 }
 
 // DriveRuntime represents a CloudSigma runtime information of the drive.
 type DriveRuntime struct {
-	IsSnapshotable         bool   `json:"is_snapshotable,omitempty"`
-	SnapshotsAllocatedSize int    `json:"snapshots_allocated_size,omitempty"`
-	StorageType            string `json:"storage_type,omitempty"`
+	IsSnapshotable         bool        `json:"is_snapshotable,omitempty"`
+	SnapshotsAllocatedSize int         `json:"snapshots_allocated_size,omitempty"`
+	StorageType            StorageType `json:"storage_type,omitempty"`
 }
 
 // DriveCreateRequest represents a request to create a drive.
@@ -51,11 +154,47 @@ type DriveUpdateRequest struct {
 	*Drive
 }
 
+// MarshalJSON is a custom marshaller for DriveUpdateRequest. Without it the
+// promoted Drive.MarshalJSON would be called on a nil Drive.
+func (r DriveUpdateRequest) MarshalJSON() ([]byte, error) {
+	if r.Drive == nil {
+		return []byte("{}"), nil
+	}
+	return r.Drive.MarshalJSON()
+}
+
+// UnmarshalJSON is a custom unmarshaller for DriveUpdateRequest. Without
+// it the promoted Drive.UnmarshalJSON would be called on a nil Drive.
+func (r *DriveUpdateRequest) UnmarshalJSON(data []byte) error {
+	if r.Drive == nil {
+		r.Drive = new(Drive)
+	}
+	return r.Drive.UnmarshalJSON(data)
+}
+
 // DriveCloneRequest represents a request to clone a drive.
 type DriveCloneRequest struct {
 	*Drive
 }
 
+// MarshalJSON is a custom marshaller for DriveCloneRequest. Without it the
+// promoted Drive.MarshalJSON would be called on a nil Drive.
+func (r DriveCloneRequest) MarshalJSON() ([]byte, error) {
+	if r.Drive == nil {
+		return []byte("{}"), nil
+	}
+	return r.Drive.MarshalJSON()
+}
+
+// UnmarshalJSON is a custom unmarshaller for DriveCloneRequest. Without
+// it the promoted Drive.UnmarshalJSON would be called on a nil Drive.
+func (r *DriveCloneRequest) UnmarshalJSON(data []byte) error {
+	if r.Drive == nil {
+		r.Drive = new(Drive)
+	}
+	return r.Drive.UnmarshalJSON(data)
+}
+
 // DriveListOptions specifies the optional parameters
 // to the DrivesService.List.
 type DriveListOptions struct {
@@ -69,6 +208,19 @@ type DriveListOptions struct {
 	ListOptions
 }
 
+// DriveBulkResult represents the outcome of a bulk operation for a single
+// drive identified by UUID. Drive is only set by DrivesService.GetMany.
+type DriveBulkResult struct {
+	UUID  string
+	Drive *Drive
+	Err   error
+}
+
+// DriveRevertRequest represents a request to revert a drive to a snapshot.
+type DriveRevertRequest struct {
+	Snapshot string `json:"snapshot"`
+}
+
 type drivesRoot struct {
 	Drives []Drive `json:"objects"`
 	Meta   *Meta   `json:"meta,omitempty"`
@@ -169,8 +321,9 @@ func (s *DrivesService) Update(ctx context.Context, uuid string, updateRequest *
 
 	path := fmt.Sprintf("%v/%v/", drivesBasePath, uuid)
 
-	// by update UUID must be empty
+	// by update UUID must be empty, and jobs are read-only
 	updateRequest.UUID = ""
+	updateRequest.Jobs = nil
 
 	req, err := s.client.NewRequest(http.MethodPut, path, updateRequest)
 	if err != nil {
@@ -206,7 +359,8 @@ func (s *DrivesService) Delete(ctx context.Context, uuid string) (*Response, err
 
 // Resize updates a drive definition. Note that the resize action is a full
 // definition update (it can update even name and metadata), so a full
-// definition should be provided to this call.
+// definition should be provided to this call. The resized drive lists the
+// resize job in Jobs, which can be awaited with JobsService.WaitForJob.
 //
 // CloudSigma API docs: https://cloudsigma-docs.readthedocs.io/en/latest/drives.html#resizing-update-or-fail
 func (s *DrivesService) Resize(ctx context.Context, uuid string, updateRequest *DriveUpdateRequest) ([]Drive, *Response, error) {
@@ -219,6 +373,11 @@ func (s *DrivesService) Resize(ctx context.Context, uuid string, updateRequest *
 
 	path := fmt.Sprintf("%v/%v/action/?do=resize", drivesBasePath, uuid)
 
+	// jobs are read-only
+	if updateRequest.Drive != nil {
+		updateRequest.Jobs = nil
+	}
+
 	req, err := s.client.NewRequest(http.MethodPost, path, updateRequest)
 	if err != nil {
 		return nil, nil, err
@@ -234,7 +393,8 @@ func (s *DrivesService) Resize(ctx context.Context, uuid string, updateRequest *
 }
 
 // Clone duplicates a drive. DriveCloneRequest is optional. Size of the
-// cloned drive can only be bigger or the same.
+// cloned drive can only be bigger or the same. The cloned drive lists the
+// clone job in Jobs, which can be awaited with JobsService.WaitForJob.
 //
 // CloudSigma API docs: https://cloudsigma-docs.readthedocs.io/en/latest/drives.html#cloning
 func (s *DrivesService) Clone(ctx context.Context, uuid string, cloneRequest *DriveCloneRequest) (*Drive, *Response, error) {
@@ -249,6 +409,10 @@ func (s *DrivesService) Clone(ctx context.Context, uuid string, cloneRequest *Dr
 		return nil, nil, err
 	}
 	if cloneRequest != nil {
+		// jobs are read-only
+		if cloneRequest.Drive != nil {
+			cloneRequest.Jobs = nil
+		}
 		req, err = s.client.NewRequest(http.MethodPost, path, cloneRequest)
 		if err != nil {
 			return nil, nil, err
@@ -263,3 +427,240 @@ func (s *DrivesService) Clone(ctx context.Context, uuid string, cloneRequest *Dr
 
 	return &root.Drives[0], resp, nil
 }
+
+// Revert rolls a drive identified by uuid back to the state of one of its
+// snapshots. The drive must not be mounted on a running server.
+//
+// CloudSigma API docs: https://cloudsigma-docs.readthedocs.io/en/latest/snapshots.html#reverting-drive-to-snapshot
+func (s *DrivesService) Revert(ctx context.Context, uuid string, revertRequest *DriveRevertRequest) (*Drive, *Response, error) {
+	if revertRequest == nil {
+		return nil, nil, ErrEmptyPayloadNotAllowed
+	}
+	if revertRequest.Snapshot == "" {
+		return nil, nil, ErrEmptyArgument
+	}
+	return s.doAction(ctx, uuid, "revert", revertRequest)
+}
+
+func (s *DrivesService) doAction(ctx context.Context, uuid, action string, body interface{}) (*Drive, *Response, error) {
+	if uuid == "" || action == "" {
+		return nil, nil, ErrEmptyArgument
+	}
+
+	path := fmt.Sprintf("%v/%v/action/?do=%v", drivesBasePath, uuid, action)
+
+	req, err := s.client.NewRequest(http.MethodPost, path, body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	drive := new(Drive)
+	resp, err := s.client.Do(ctx, req, drive)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return drive, resp, nil
+}
+
+// Upload creates a new drive from raw image data read from r. size is the
+// exact number of bytes in r. The upload is sent to the direct upload API of
+// the client location. The uploaded drive has the default media disk and can
+// be changed with DrivesService.Update.
+//
+// CloudSigma API docs: https://cloudsigma-docs.readthedocs.io/en/latest/upload.html
+func (s *DrivesService) Upload(ctx context.Context, r io.Reader, size int64) (*Drive, *Response, error) {
+	if r == nil || size <= 0 {
+		return nil, nil, ErrEmptyArgument
+	}
+
+	u := *s.client.baseURL
+	if strings.HasSuffix(u.Host, ".cloudsigma.com") {
+		u.Host = directUploadHostPrefix + u.Host
+	}
+	path := fmt.Sprintf("%v%v/upload/", u.String(), drivesBasePath)
+
+	req, err := s.client.NewRequest(http.MethodPost, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Body = io.NopCloser(r)
+	req.ContentLength = size
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	buf := new(bytes.Buffer)
+	resp, err := s.client.Do(ctx, req, buf)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	uuid := strings.TrimSpace(buf.String())
+	if uuid == "" {
+		return nil, resp, ErrResourceNotFound
+	}
+
+	return s.Get(ctx, uuid)
+}
+
+// WaitForStatus polls a drive identified by uuid until it reaches the given
+// status, and returns the drive in that status. ErrDriveUnavailable is
+// returned as soon as the drive becomes unavailable instead, e.g. when a clone
+// failed. Use a context with a deadline to limit the waiting time.
+func (s *DrivesService) WaitForStatus(ctx context.Context, uuid string, status DriveStatus) (*Drive, error) {
+	if uuid == "" || status == "" {
+		return nil, ErrEmptyArgument
+	}
+
+	var drive *Drive
+	err := s.client.poll(ctx, func() (bool, error) {
+		d, _, err := s.Get(ctx, uuid)
+		if err != nil {
+			return false, err
+		}
+		drive = d
+		if drive.Status == DriveStatusUnavailable && status != DriveStatusUnavailable {
+			return false, ErrDriveUnavailable
+		}
+		return drive.Status == status, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return drive, nil
+}
+
+// GetMany provides detailed information for drives identified by uuids. The
+// uuids are split into several list requests if needed. The result contains
+// an entry for every unique uuid in the given order. If some drives cannot be
+// fetched, the returned error is a *BulkError listing the failed uuids.
+//
+// CloudSigma API docs: https://cloudsigma-docs.readthedocs.io/en/latest/drives.html#detailed-listing
+func (s *DrivesService) GetMany(ctx context.Context, uuids []string) ([]DriveBulkResult, error) {
+	if len(uuids) == 0 {
+		return nil, ErrEmptyArgument
+	}
+
+	uuids = uniqueUUIDs(uuids)
+	results := make([]DriveBulkResult, 0, len(uuids))
+	for _, chunk := range chunkUUIDs(uuids, maxBulkUUIDsLength) {
+		opts := &DriveListOptions{UUIDs: chunk, ListOptions: ListOptions{Limit: 0}}
+		drives, _, err := s.List(ctx, opts)
+		if err != nil && ctx.Err() != nil {
+			return nil, err
+		}
+
+		found := make(map[string]*Drive, len(drives))
+		for i := range drives {
+			found[drives[i].UUID] = &drives[i]
+		}
+		for _, uuid := range chunk {
+			result := DriveBulkResult{UUID: uuid}
+			switch {
+			case err != nil:
+				result.Err = err
+			case found[uuid] == nil:
+				result.Err = ErrResourceNotFound
+			default:
+				result.Drive = found[uuid]
+			}
+			results = append(results, result)
+		}
+	}
+
+	return results, bulkResultsError(results)
+}
+
+// DeleteMany removes drives identified by uuids. The uuids are split into
+// several bulk delete requests if needed. The result contains an entry for
+// every unique uuid in the given order. If a bulk request fails, all drives
+// of that request are reported as failed with the request error, and the
+// returned error is a *BulkError listing the failed uuids.
+//
+// CloudSigma API docs: https://cloudsigma-docs.readthedocs.io/en/latest/drives.html#deleting
+func (s *DrivesService) DeleteMany(ctx context.Context, uuids []string) ([]DriveBulkResult, error) {
+	if len(uuids) == 0 {
+		return nil, ErrEmptyArgument
+	}
+
+	path := fmt.Sprintf("%v/", drivesBasePath)
+
+	uuids = uniqueUUIDs(uuids)
+	results := make([]DriveBulkResult, 0, len(uuids))
+	for _, chunk := range chunkUUIDs(uuids, maxBulkUUIDsLength) {
+		root := &drivesRoot{Drives: make([]Drive, 0, len(chunk))}
+		for _, uuid := range chunk {
+			root.Drives = append(root.Drives, Drive{UUID: uuid})
+		}
+
+		req, err := s.client.NewRequest(http.MethodDelete, path, root)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = s.client.Do(ctx, req, nil)
+		if err != nil && ctx.Err() != nil {
+			return nil, err
+		}
+		for _, uuid := range chunk {
+			results = append(results, DriveBulkResult{UUID: uuid, Err: err})
+		}
+	}
+
+	return results, bulkResultsError(results)
+}
+
+// bulkResultsError returns a *BulkError for all failed results, or nil if
+// every result succeeded.
+func bulkResultsError(results []DriveBulkResult) error {
+	var failures []BulkFailure
+	for _, r := range results {
+		if r.Err != nil {
+			failures = append(failures, BulkFailure{UUID: r.UUID, Err: r.Err})
+		}
+	}
+	if len(failures) == 0 {
+		return nil
+	}
+	return &BulkError{Failures: failures}
+}
+
+// uniqueUUIDs returns uuids without empty and duplicated values, keeping
+// the original order.
+func uniqueUUIDs(uuids []string) []string {
+	seen := make(map[string]bool, len(uuids))
+	unique := make([]string, 0, len(uuids))
+	for _, uuid := range uuids {
+		if uuid == "" || seen[uuid] {
+			continue
+		}
+		seen[uuid] = true
+		unique = append(unique, uuid)
+	}
+	return unique
+}
+
+// chunkUUIDs splits uuids into chunks, so that the comma separated values of
+// a single chunk do not exceed maxLength. A single uuid longer than maxLength
+// is returned as its own chunk.
+func chunkUUIDs(uuids []string, maxLength int) [][]string {
+	var chunks [][]string
+	var chunk []string
+	length := 0
+	for _, uuid := range uuids {
+		size := len(uuid)
+		if len(chunk) > 0 {
+			size++ // separating comma
+		}
+		if len(chunk) > 0 && length+size > maxLength {
+			chunks = append(chunks, chunk)
+			chunk, length, size = nil, 0, len(uuid)
+		}
+		chunk = append(chunk, uuid)
+		length += size
+	}
+	if len(chunk) > 0 {
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
@@ -1,10 +1,13 @@
 package cloudsigma
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -123,6 +126,47 @@ func TestDrives_Update(t *testing.T) {
 	assert.Equal(t, expected, drive)
 }
 
+func TestDrives_Update_extraFields(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/drives/long-uuid/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			_, _ = fmt.Fprint(w, `{"name":"test drive","uuid":"long-uuid","size":1024,"encryption":{"enabled":true}}`)
+			return
+		}
+		v := make(map[string]interface{})
+		_ = json.NewDecoder(r.Body).Decode(&v)
+		assert.Equal(t, map[string]interface{}{"enabled": true}, v["encryption"])
+		assert.Equal(t, float64(2048), v["size"])
+		_, _ = fmt.Fprint(w, `{"name":"test drive","uuid":"long-uuid","size":2048}`)
+	})
+	drive, _, err := client.Drives.Get(ctx, "long-uuid")
+	assert.NoError(t, err)
+	drive.Size = 2048
+
+	drive, _, err = client.Drives.Update(ctx, drive.UUID, &DriveUpdateRequest{Drive: drive})
+
+	assert.NoError(t, err)
+	assert.Nil(t, drive.Extra)
+}
+
+func TestDrives_MarshalJSON(t *testing.T) {
+	drive := Drive{Name: "test drive", Extra: map[string]json.RawMessage{"storage_type": json.RawMessage(`"dssd"`)}}
+
+	data, err := json.Marshal(drive)
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"name":"test drive","storage_type":"dssd"}`, string(data))
+
+	for _, request := range []interface{}{&DriveUpdateRequest{}, &DriveCloneRequest{}, DriveCloneRequest{}} {
+		data, err = json.Marshal(request)
+
+		assert.NoError(t, err)
+		assert.Equal(t, `{}`, string(data))
+	}
+}
+
 func TestDrives_Update_emptyUUID(t *testing.T) {
 	input := &DriveUpdateRequest{
 		Drive: &Drive{
@@ -258,6 +302,48 @@ func TestDrives_Clone(t *testing.T) {
 	assert.Equal(t, expected, drive)
 }
 
+func TestDrives_Clone_readOnlyJobs(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/drives/long-uuid/action/", func(w http.ResponseWriter, r *http.Request) {
+		v := make(map[string]interface{})
+		_ = json.NewDecoder(r.Body).Decode(&v)
+		assert.Equal(t, map[string]interface{}{"name": "clone"}, v)
+		_, _ = fmt.Fprint(w, `{"objects":[{"name":"clone","uuid":"generated-uuid"}]}`)
+	})
+	mux.HandleFunc("/drives/other-uuid/", func(w http.ResponseWriter, r *http.Request) {
+		v := make(map[string]interface{})
+		_ = json.NewDecoder(r.Body).Decode(&v)
+		assert.Equal(t, map[string]interface{}{"name": "renamed"}, v)
+		_, _ = fmt.Fprint(w, `{"name":"renamed","uuid":"other-uuid"}`)
+	})
+	jobs := []ResourceLink{{UUID: "job-uuid"}}
+
+	_, _, err := client.Drives.Clone(ctx, "long-uuid", &DriveCloneRequest{Drive: &Drive{Name: "clone", Jobs: jobs}})
+
+	assert.NoError(t, err)
+
+	_, _, err = client.Drives.Update(ctx, "other-uuid", &DriveUpdateRequest{Drive: &Drive{Name: "renamed", Jobs: jobs, UUID: "other-uuid"}})
+
+	assert.NoError(t, err)
+}
+
+func TestDrives_Clone_job(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/drives/long-uuid/action/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"objects":[{"jobs":[{"resource_uri":"/api/2.0/jobs/job-uuid/","uuid":"job-uuid"}],"uuid":"generated-uuid"}]}`)
+	})
+	expected := []ResourceLink{{ResourceURI: "/api/2.0/jobs/job-uuid/", UUID: "job-uuid"}}
+
+	drive, _, err := client.Drives.Clone(ctx, "long-uuid", nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, drive.Jobs)
+}
+
 func TestDrives_Clone_emptyPayload(t *testing.T) {
 	setup()
 	defer teardown()
@@ -301,3 +387,286 @@ func TestDrives_Clone_invalidUUID(t *testing.T) {
 
 	assert.Error(t, err)
 }
+
+func TestDrives_GetMany(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/drives/detail/", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "uuid-1,uuid-2,uuid-3", r.URL.Query().Get("uuid"))
+		assert.Equal(t, "0", r.URL.Query().Get("limit"))
+		_, _ = fmt.Fprint(w, `{"objects":[{"name":"drive 3","uuid":"uuid-3"},{"name":"drive 1","uuid":"uuid-1"}],"meta":{"total_count":2}}`)
+	})
+	expected := []DriveBulkResult{
+		{UUID: "uuid-1", Drive: &Drive{Name: "drive 1", UUID: "uuid-1"}},
+		{UUID: "uuid-2", Err: ErrResourceNotFound},
+		{UUID: "uuid-3", Drive: &Drive{Name: "drive 3", UUID: "uuid-3"}},
+	}
+
+	results, err := client.Drives.GetMany(ctx, []string{"uuid-1", "uuid-2", "uuid-1", "uuid-3"})
+
+	assert.Equal(t, expected, results)
+	var bulkErr *BulkError
+	assert.ErrorAs(t, err, &bulkErr)
+	assert.Equal(t, []BulkFailure{{UUID: "uuid-2", Err: ErrResourceNotFound}}, bulkErr.Failures)
+	assert.ErrorIs(t, err, ErrResourceNotFound)
+}
+
+func TestDrives_GetMany_chunked(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var uuids []string
+	for i := 0; i < 100; i++ {
+		uuids = append(uuids, fmt.Sprintf("00000000-0000-0000-0000-%012d", i))
+	}
+	requests := 0
+	mux.HandleFunc("/drives/detail/", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		query := r.URL.Query().Get("uuid")
+		assert.LessOrEqual(t, len(query), maxBulkUUIDsLength)
+		root := new(drivesRoot)
+		for _, uuid := range strings.Split(query, ",") {
+			root.Drives = append(root.Drives, Drive{UUID: uuid})
+		}
+		_ = json.NewEncoder(w).Encode(root)
+	})
+
+	results, err := client.Drives.GetMany(ctx, uuids)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, requests)
+	assert.Len(t, results, 100)
+	for i, result := range results {
+		assert.Equal(t, uuids[i], result.UUID)
+		assert.Equal(t, uuids[i], result.Drive.UUID)
+	}
+}
+
+func TestDrives_GetMany_emptyUUIDs(t *testing.T) {
+	_, err := client.Drives.GetMany(ctx, nil)
+
+	assert.Error(t, err)
+	assert.Equal(t, ErrEmptyArgument.Error(), err.Error())
+}
+
+func TestDrives_DeleteMany(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/drives/", func(w http.ResponseWriter, r *http.Request) {
+		v := new(drivesRoot)
+		_ = json.NewDecoder(r.Body).Decode(v)
+		assert.Equal(t, http.MethodDelete, r.Method)
+		assert.Equal(t, []Drive{{UUID: "uuid-1"}, {UUID: "uuid-2"}}, v.Drives)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	expected := []DriveBulkResult{
+		{UUID: "uuid-1"},
+		{UUID: "uuid-2"},
+	}
+
+	results, err := client.Drives.DeleteMany(ctx, []string{"uuid-1", "uuid-2"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, results)
+}
+
+func TestDrives_DeleteMany_partialFailure(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var uuids []string
+	for i := 0; i < 50; i++ {
+		uuids = append(uuids, fmt.Sprintf("00000000-0000-0000-0000-%012d", i))
+	}
+	requests := 0
+	mux.HandleFunc("/drives/", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 2 {
+			w.WriteHeader(http.StatusConflict)
+			_, _ = fmt.Fprint(w, `[{"error_message":"drive is mounted","error_type":"permission"}]`)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	results, err := client.Drives.DeleteMany(ctx, uuids)
+
+	assert.Len(t, results, 50)
+	var bulkErr *BulkError
+	assert.ErrorAs(t, err, &bulkErr)
+	assert.Len(t, bulkErr.Failures, 10)
+	assert.NoError(t, results[39].Err)
+	var errResp *ErrorResponse
+	assert.ErrorAs(t, results[40].Err, &errResp)
+	assert.Equal(t, http.StatusConflict, errResp.Response.StatusCode)
+}
+
+func TestDrives_DeleteMany_emptyUUIDs(t *testing.T) {
+	_, err := client.Drives.DeleteMany(ctx, []string{})
+
+	assert.Error(t, err)
+	assert.Equal(t, ErrEmptyArgument.Error(), err.Error())
+}
+
+func TestDrives_chunkUUIDs(t *testing.T) {
+	chunks := chunkUUIDs([]string{"aaaa", "bbbb", "cccc", "dddddddddddd"}, 9)
+
+	assert.Equal(t, [][]string{{"aaaa", "bbbb"}, {"cccc"}, {"dddddddddddd"}}, chunks)
+}
+
+func TestDrives_WaitForStatus(t *testing.T) {
+	setup()
+	defer teardown()
+
+	polls := 0
+	mux.HandleFunc("/drives/long-uuid/", func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		if polls < 2 {
+			_, _ = fmt.Fprint(w, `{"status":"creating","uuid":"long-uuid"}`)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"status":"unmounted","uuid":"long-uuid"}`)
+	})
+
+	drive, err := client.Drives.WaitForStatus(ctx, "long-uuid", DriveStatusUnmounted)
+
+	assert.NoError(t, err)
+	assert.Equal(t, DriveStatusUnmounted, drive.Status)
+	assert.Equal(t, 2, polls)
+}
+
+func TestDrives_WaitForStatus_contextDone(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/drives/long-uuid/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"status":"creating","uuid":"long-uuid"}`)
+	})
+	ctx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+
+	_, err := client.Drives.WaitForStatus(ctx, "long-uuid", "unmounted")
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestDrives_WaitForStatus_unavailable(t *testing.T) {
+	setup()
+	defer teardown()
+
+	polls := 0
+	mux.HandleFunc("/drives/long-uuid/", func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		_, _ = fmt.Fprint(w, `{"status":"unavailable","uuid":"long-uuid"}`)
+	})
+
+	_, err := client.Drives.WaitForStatus(ctx, "long-uuid", DriveStatusUnmounted)
+
+	assert.ErrorIs(t, err, ErrDriveUnavailable)
+	assert.Equal(t, 1, polls)
+}
+
+func TestDrives_WaitForStatus_emptyUUID(t *testing.T) {
+	_, err := client.Drives.WaitForStatus(ctx, "", "unmounted")
+
+	assert.Error(t, err)
+	assert.Equal(t, ErrEmptyArgument.Error(), err.Error())
+}
+
+func TestDrives_Revert(t *testing.T) {
+	setup()
+	defer teardown()
+
+	input := &DriveRevertRequest{Snapshot: "snapshot-uuid"}
+	mux.HandleFunc("/drives/long-uuid/action/", func(w http.ResponseWriter, r *http.Request) {
+		v := new(DriveRevertRequest)
+		_ = json.NewDecoder(r.Body).Decode(v)
+		assert.Equal(t, input, v)
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "revert", r.URL.Query().Get("do"))
+		_, _ = fmt.Fprint(w, `{"status":"unmounted","uuid":"long-uuid"}`)
+	})
+	expected := &Drive{
+		Status: "unmounted",
+		UUID:   "long-uuid",
+	}
+
+	drive, _, err := client.Drives.Revert(ctx, "long-uuid", input)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, drive)
+}
+
+func TestDrives_Revert_emptyUUID(t *testing.T) {
+	_, _, err := client.Drives.Revert(ctx, "", &DriveRevertRequest{Snapshot: "snapshot-uuid"})
+
+	assert.Error(t, err)
+	assert.Equal(t, ErrEmptyArgument.Error(), err.Error())
+}
+
+func TestDrives_Revert_emptyPayload(t *testing.T) {
+	_, _, err := client.Drives.Revert(ctx, "long-uuid", nil)
+
+	assert.Error(t, err)
+	assert.Equal(t, ErrEmptyPayloadNotAllowed.Error(), err.Error())
+}
+
+func TestDrives_Upload(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/drives/upload/", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/octet-stream", r.Header.Get("Content-Type"))
+		assert.Equal(t, int64(5), r.ContentLength)
+		_, _ = fmt.Fprint(w, "long-uuid\n")
+	})
+	mux.HandleFunc("/drives/long-uuid/", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		_, _ = fmt.Fprint(w, `{"name":"upload","uuid":"long-uuid"}`)
+	})
+	expected := &Drive{
+		Name: "upload",
+		UUID: "long-uuid",
+	}
+
+	drive, _, err := client.Drives.Upload(ctx, strings.NewReader("image"), 5)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, drive)
+}
+
+func TestDrives_Upload_emptyReader(t *testing.T) {
+	_, _, err := client.Drives.Upload(ctx, nil, 0)
+
+	assert.Error(t, err)
+	assert.Equal(t, ErrEmptyArgument.Error(), err.Error())
+}
+
+func TestDriveStatus(t *testing.T) {
+	assert.True(t, DriveStatusMounted.IsValid())
+	assert.False(t, DriveStatus("moutned").IsValid())
+	assert.True(t, DriveStatusCopying.IsTransitional())
+	assert.True(t, DriveStatusCloningDestination.IsValid())
+	assert.True(t, DriveStatusCloningSource.IsTransitional())
+	assert.False(t, DriveStatusUnmounted.IsTransitional())
+	assert.True(t, DriveMediaCDROM.IsValid())
+	assert.False(t, DriveMedia("floppy").IsValid())
+	assert.True(t, StorageTypeMagnetic.IsValid())
+	assert.False(t, StorageType("dsdd").IsValid())
+}
+
+func TestDrive_unknownEnumValues(t *testing.T) {
+	drive := new(Drive)
+
+	err := json.Unmarshal([]byte(`{"media":"tape","status":"migrating","storage_type":"nvme"}`), drive)
+
+	assert.NoError(t, err)
+	assert.Equal(t, DriveStatus("migrating"), drive.Status)
+	assert.False(t, drive.Status.IsValid())
+	data, _ := json.Marshal(drive)
+	assert.JSONEq(t, `{"media":"tape","status":"migrating","storage_type":"nvme"}`, string(data))
+}
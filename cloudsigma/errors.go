@@ -3,6 +3,7 @@ package cloudsigma
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
 // Errors used by the CloudSigma SDK.
@@ -13,6 +14,18 @@ var (
 
 	// ErrEmptyArgument is returned when a mandatory function argument is empty.
 	ErrEmptyArgument = errors.New("cloudsigma-sdk-go: argument cannot be empty")
+
+	// ErrResourceNotFound is returned when a requested resource is missing
+	// from an otherwise successful API response.
+	ErrResourceNotFound = errors.New("cloudsigma-sdk-go: resource not found")
+
+	// ErrDriveUnavailable is returned when a drive became unavailable while
+	// waiting for another status.
+	ErrDriveUnavailable = errors.New("cloudsigma-sdk-go: drive is unavailable")
+
+	// ErrConflict is returned when a resource could not be changed because
+	// of concurrent updates.
+	ErrConflict = errors.New("cloudsigma-sdk-go: conflicting concurrent update")
 )
 
 // An ErrorResponse reports one or more errors caused by an API request.
@@ -39,3 +52,34 @@ func (r *ErrorResponse) Error() string {
 	return fmt.Sprintf("%v %v: %d %+v",
 		r.Response.Request.Method, r.Response.Request.URL, r.Response.StatusCode, r.Errors)
 }
+
+// A BulkError reports the resources that failed during a bulk operation.
+// Resources not listed in Failures were processed successfully.
+type BulkError struct {
+	Failures []BulkFailure
+}
+
+// BulkFailure represents a single failed resource of a bulk operation.
+type BulkFailure struct {
+	UUID string
+	Err  error
+}
+
+// Error represents a string error message with all failed resources.
+func (e *BulkError) Error() string {
+	messages := make([]string, 0, len(e.Failures))
+	for _, f := range e.Failures {
+		messages = append(messages, fmt.Sprintf("%v: %v", f.UUID, f.Err))
+	}
+	return fmt.Sprintf("cloudsigma-sdk-go: %d bulk operation(s) failed: %v", len(e.Failures), strings.Join(messages, "; "))
+}
+
+// Unwrap returns the errors of all failed resources, so errors.Is and
+// errors.As can be used on a BulkError.
+func (e *BulkError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Failures))
+	for _, f := range e.Failures {
+		errs = append(errs, f.Err)
+	}
+	return errs
+}
@@ -51,3 +51,17 @@ func TestErrors_Error_quotedRequestID(t *testing.T) {
 	assert.Error(t, errorResponse)
 	assert.Equal(t, expectedMessage, errorResponse.Error())
 }
+
+func TestErrors_BulkError(t *testing.T) {
+	bulkErr := &BulkError{
+		Failures: []BulkFailure{
+			{UUID: "uuid-1", Err: ErrResourceNotFound},
+			{UUID: "uuid-2", Err: ErrEmptyArgument},
+		},
+	}
+	expectedMessage := "cloudsigma-sdk-go: 2 bulk operation(s) failed: uuid-1: cloudsigma-sdk-go: resource not found; uuid-2: cloudsigma-sdk-go: argument cannot be empty"
+
+	assert.Equal(t, expectedMessage, bulkErr.Error())
+	assert.ErrorIs(t, bulkErr, ErrResourceNotFound)
+	assert.ErrorIs(t, bulkErr, ErrEmptyArgument)
+}
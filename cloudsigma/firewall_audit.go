@@ -0,0 +1,276 @@
+package cloudsigma
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// DefaultManagementPorts are the ports checked by a FirewallAuditor if
+// FirewallAuditOptions.ManagementPorts is empty: SSH, RDP and VNC.
+var DefaultManagementPorts = []uint16{22, 3389, 5900}
+
+// Severity represents the severity of an audit finding.
+type Severity string
+
+// Audit finding severities.
+const (
+	SeverityLow    Severity = "low"
+	SeverityMedium Severity = "medium"
+	SeverityHigh   Severity = "high"
+)
+
+// Rank returns 1, 2 and 3 for low, medium and high severities, and 0 for
+// unknown ones, so severities can be compared.
+func (s Severity) Rank() int {
+	switch s {
+	case SeverityLow:
+		return 1
+	case SeverityMedium:
+		return 2
+	case SeverityHigh:
+		return 3
+	}
+	return 0
+}
+
+// FirewallFindingKind represents the kind of a firewall audit finding.
+type FirewallFindingKind string
+
+// Firewall audit finding kinds.
+const (
+	// FirewallFindingManagementPortOpen reports a management port open to
+	// all IPv4 or IPv6 addresses.
+	FirewallFindingManagementPortOpen FirewallFindingKind = "management_port_open"
+	// FirewallFindingUnprotectedNIC reports a public NIC without a policy.
+	FirewallFindingUnprotectedNIC FirewallFindingKind = "unprotected_nic"
+	// FirewallFindingUnusedPolicy reports a policy attached to no server.
+	FirewallFindingUnusedPolicy FirewallFindingKind = "unused_policy"
+	// FirewallFindingDuplicateRule reports a rule covered by an earlier rule
+	// with the same action.
+	FirewallFindingDuplicateRule FirewallFindingKind = "duplicate_rule"
+	// FirewallFindingContradictoryRule reports a rule covered by an earlier
+	// rule with a different action.
+	FirewallFindingContradictoryRule FirewallFindingKind = "contradictory_rule"
+	// FirewallFindingInvalidRule reports a rule which is invalid or matches
+	// no packet.
+	FirewallFindingInvalidRule FirewallFindingKind = "invalid_rule"
+)
+
+// FirewallFinding represents a single problem found by a FirewallAuditor.
+// Servers lists the UUIDs of the affected servers. RuleIndex is the index of
+// the affected rule in the policy, or -1.
+type FirewallFinding struct {
+	Severity   Severity
+	Kind       FirewallFindingKind
+	PolicyUUID string
+	PolicyName string
+	Servers    []string
+	RuleIndex  int
+	Message    string
+}
+
+// FirewallAuditReport represents the findings of a FirewallAuditor, ordered
+// by descending severity.
+type FirewallAuditReport struct {
+	Policies int
+	Servers  int
+	Findings []FirewallFinding
+}
+
+// MaxSeverity returns the highest severity of the findings, or "" if there
+// are none.
+func (r *FirewallAuditReport) MaxSeverity() Severity {
+	var severity Severity
+	for _, f := range r.Findings {
+		if f.Severity.Rank() > severity.Rank() {
+			severity = f.Severity
+		}
+	}
+	return severity
+}
+
+// FirewallAuditOptions specifies the optional parameters of a
+// FirewallAuditor.
+type FirewallAuditOptions struct {
+	// ManagementPorts must not be open to all addresses. Defaults to
+	// DefaultManagementPorts.
+	ManagementPorts []uint16
+}
+
+// FirewallAuditor checks the firewall policies and server NICs of an
+// account.
+type FirewallAuditor struct {
+	client *Client
+	opts   FirewallAuditOptions
+}
+
+// NewFirewallAuditor returns a new FirewallAuditor.
+func NewFirewallAuditor(client *Client, opts *FirewallAuditOptions) *FirewallAuditor {
+	a := &FirewallAuditor{client: client}
+	if opts != nil {
+		a.opts = *opts
+	}
+	if len(a.opts.ManagementPorts) == 0 {
+		a.opts.ManagementPorts = DefaultManagementPorts
+	}
+	return a
+}
+
+// Audit lists all firewall policies and servers and reports:
+//
+//   - management ports open to 0.0.0.0/0 or ::/0, either by an accept rule or
+//     by the default accept of CloudSigma (high)
+//   - public NICs, i.e. NICs with an IP configuration and no VLAN, without
+//     a firewall policy (high)
+//   - contradictory and invalid rules (medium)
+//   - duplicate rules and policies attached to no server (low)
+//
+// Policies are mapped to servers by FirewallPolicy.Servers and by the
+// policies of the server NICs.
+func (a *FirewallAuditor) Audit(ctx context.Context) (*FirewallAuditReport, error) {
+	if a.client == nil {
+		return nil, ErrEmptyArgument
+	}
+
+	policies, _, err := a.client.FirewallPolicies.List(ctx, &ListOptions{Limit: 0})
+	if err != nil {
+		return nil, err
+	}
+	servers, _, err := a.client.Servers.List(ctx, &ListOptions{Limit: 0})
+	if err != nil {
+		return nil, err
+	}
+
+	report := &FirewallAuditReport{Policies: len(policies), Servers: len(servers)}
+	attached := make(map[string]map[string]bool)
+	attach := func(policyUUID, serverUUID string) {
+		if attached[policyUUID] == nil {
+			attached[policyUUID] = make(map[string]bool)
+		}
+		attached[policyUUID][serverUUID] = true
+	}
+	for _, policy := range policies {
+		for _, server := range policy.Servers {
+			attach(policy.UUID, server.UUID)
+		}
+	}
+	for _, server := range servers {
+		for _, nic := range server.NICs {
+			if nic.FirewallPolicy != nil && nic.FirewallPolicy.UUID != "" {
+				attach(nic.FirewallPolicy.UUID, server.UUID)
+				continue
+			}
+			if isPublicNIC(&nic) {
+				report.Findings = append(report.Findings, FirewallFinding{
+					Severity:  SeverityHigh,
+					Kind:      FirewallFindingUnprotectedNIC,
+					Servers:   []string{server.UUID},
+					RuleIndex: -1,
+					Message:   fmt.Sprintf("public NIC %v of server %q has no firewall policy", nic.MACAddress, server.Name),
+				})
+			}
+		}
+	}
+
+	for i := range policies {
+		policy := &policies[i]
+		servers := make([]string, 0, len(attached[policy.UUID]))
+		for uuid := range attached[policy.UUID] {
+			servers = append(servers, uuid)
+		}
+		sort.Strings(servers)
+		finding := func(severity Severity, kind FirewallFindingKind, ruleIndex int, format string, args ...interface{}) {
+			report.Findings = append(report.Findings, FirewallFinding{
+				Severity:   severity,
+				Kind:       kind,
+				PolicyUUID: policy.UUID,
+				PolicyName: policy.Name,
+				Servers:    servers,
+				RuleIndex:  ruleIndex,
+				Message:    fmt.Sprintf(format, args...),
+			})
+		}
+
+		if len(servers) == 0 {
+			finding(SeverityLow, FirewallFindingUnusedPolicy, -1, "policy %q is attached to no server", policy.Name)
+		}
+		for _, port := range a.opts.ManagementPorts {
+			for _, family := range []string{"0.0.0.0/0", "::/0"} {
+				ruleIndex, open := openToAll(policy, family, port)
+				if !open {
+					continue
+				}
+				if ruleIndex < 0 {
+					finding(SeverityHigh, FirewallFindingManagementPortOpen, -1,
+						"port %d is open to %v by the default accept policy", port, family)
+				} else {
+					finding(SeverityHigh, FirewallFindingManagementPortOpen, ruleIndex,
+						"port %d is open to %v by rule %d", port, family, ruleIndex)
+				}
+			}
+		}
+		for _, issue := range LintFirewallPolicy(policy) {
+			switch issue.Kind {
+			case FirewallLintRedundant:
+				finding(SeverityLow, FirewallFindingDuplicateRule, issue.RuleIndex, "%v", issue.Message)
+			case FirewallLintShadowed:
+				finding(SeverityMedium, FirewallFindingContradictoryRule, issue.RuleIndex, "%v", issue.Message)
+			default:
+				finding(SeverityMedium, FirewallFindingInvalidRule, issue.RuleIndex, "%v", issue.Message)
+			}
+		}
+	}
+
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		return report.Findings[i].Severity.Rank() > report.Findings[j].Severity.Rank()
+	})
+	return report, nil
+}
+
+// isPublicNIC reports whether the NIC has a public IPv4 or IPv6
+// configuration.
+func isPublicNIC(nic *ServerNIC) bool {
+	if nic.VLAN != nil && nic.VLAN.UUID != "" {
+		return false
+	}
+	for _, conf := range []*ServerIPConfiguration{nic.IP4Configuration, nic.IP6Configuration} {
+		if conf != nil && (conf.Type == IPConfigurationDHCP || conf.Type == IPConfigurationStatic) {
+			return true
+		}
+	}
+	return false
+}
+
+// openToAll reports whether incoming TCP packets from every address of the
+// family prefix to the port are accepted, and returns the index of the
+// accepting rule, or -1 for the default policy. Only rules matching every
+// address of the family are considered. Accept rules limited to a
+// destination address count, as the destination is usually the server
+// itself, while drop rules only count if they apply to all destinations.
+// Invalid rules are ignored.
+func openToAll(policy *FirewallPolicy, family string, port uint16) (int, bool) {
+	all, _ := parseFirewallPrefix(family)
+	for i := range policy.Rules {
+		m, err := compileFirewallRule(&policy.Rules[i])
+		if err != nil || m.direction != FirewallDirectionIn {
+			continue
+		}
+		if m.protocol != "" && m.protocol != FirewallProtocolTCP {
+			continue
+		}
+		if !prefixCovers(m.src, all) || !portsContain(m.dstPorts, port) || m.srcPorts != nil {
+			continue
+		}
+		if policy.Rules[i].Action == FirewallActionAccept {
+			if m.dst == nil || m.dst.Addr().Is4() == all.Addr().Is4() {
+				return i, true
+			}
+			continue
+		}
+		if prefixCovers(m.dst, all) {
+			return -1, false
+		}
+	}
+	return -1, FirewallDefaultAction == FirewallActionAccept
+}
@@ -0,0 +1,96 @@
+package cloudsigma
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFirewallAuditor_Audit(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/fwpolicies/detail/", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "0", r.URL.Query().Get("limit"))
+		_, _ = fmt.Fprint(w, `{"objects":[`+
+			`{"name":"web","uuid":"web-uuid","servers":[{"uuid":"server-1"}],"rules":[`+
+			`{"action":"accept","direction":"in","ip_proto":"tcp","dst_port":"22","src_ip":"10.0.0.0/8"},`+
+			`{"action":"accept","direction":"in","ip_proto":"tcp","dst_port":"80,443"},`+
+			`{"action":"accept","direction":"in","ip_proto":"tcp","dst_port":"443"},`+
+			`{"action":"drop","direction":"in","ip_proto":"tcp","dst_port":"80"},`+
+			`{"action":"drop","direction":"in","src_ip":"0.0.0.0/0"}]},`+
+			`{"name":"admin","uuid":"admin-uuid","rules":[`+
+			`{"action":"accept","direction":"in","ip_proto":"tcp","dst_port":"3389","src_ip":"0.0.0.0/0"},`+
+			`{"action":"drop","direction":"in"}]},`+
+			`{"name":"unused","uuid":"unused-uuid","rules":[{"action":"drop","direction":"in"}]}`+
+			`],"meta":{"total_count":3}}`)
+	})
+	mux.HandleFunc("/servers/detail/", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "0", r.URL.Query().Get("limit"))
+		_, _ = fmt.Fprint(w, `{"objects":[`+
+			`{"name":"web","uuid":"server-1","nics":[{"mac":"22:00:00:00:00:01","ip_v4_conf":{"conf":"dhcp"},"firewall_policy":{"uuid":"web-uuid"}}]},`+
+			`{"name":"admin","uuid":"server-2","nics":[`+
+			`{"mac":"22:00:00:00:00:02","ip_v4_conf":{"conf":"static"},"firewall_policy":{"uuid":"admin-uuid"}},`+
+			`{"mac":"22:00:00:00:00:03","vlan":{"uuid":"vlan-uuid"}}]},`+
+			`{"name":"exposed","uuid":"server-3","nics":[{"mac":"22:00:00:00:00:04","ip_v6_conf":{"conf":"dhcp"}}]}`+
+			`],"meta":{"total_count":3}}`)
+	})
+
+	report, err := NewFirewallAuditor(client, nil).Audit(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, report.Policies)
+	assert.Equal(t, 3, report.Servers)
+	assert.Equal(t, SeverityHigh, report.MaxSeverity())
+	assert.Equal(t, []FirewallFinding{
+		{Severity: SeverityHigh, Kind: FirewallFindingUnprotectedNIC, Servers: []string{"server-3"}, RuleIndex: -1,
+			Message: `public NIC 22:00:00:00:00:04 of server "exposed" has no firewall policy`},
+		{Severity: SeverityHigh, Kind: FirewallFindingManagementPortOpen, PolicyUUID: "web-uuid", PolicyName: "web", Servers: []string{"server-1"}, RuleIndex: -1,
+			Message: "port 22 is open to ::/0 by the default accept policy"},
+		{Severity: SeverityHigh, Kind: FirewallFindingManagementPortOpen, PolicyUUID: "web-uuid", PolicyName: "web", Servers: []string{"server-1"}, RuleIndex: -1,
+			Message: "port 3389 is open to ::/0 by the default accept policy"},
+		{Severity: SeverityHigh, Kind: FirewallFindingManagementPortOpen, PolicyUUID: "web-uuid", PolicyName: "web", Servers: []string{"server-1"}, RuleIndex: -1,
+			Message: "port 5900 is open to ::/0 by the default accept policy"},
+		{Severity: SeverityHigh, Kind: FirewallFindingManagementPortOpen, PolicyUUID: "admin-uuid", PolicyName: "admin", Servers: []string{"server-2"}, RuleIndex: 0,
+			Message: "port 3389 is open to 0.0.0.0/0 by rule 0"},
+		{Severity: SeverityMedium, Kind: FirewallFindingContradictoryRule, PolicyUUID: "web-uuid", PolicyName: "web", Servers: []string{"server-1"}, RuleIndex: 3,
+			Message: "all packets are matched by rule 1, which accepts them"},
+		{Severity: SeverityLow, Kind: FirewallFindingDuplicateRule, PolicyUUID: "web-uuid", PolicyName: "web", Servers: []string{"server-1"}, RuleIndex: 2,
+			Message: "all packets are already matched by rule 1"},
+		{Severity: SeverityLow, Kind: FirewallFindingUnusedPolicy, PolicyUUID: "unused-uuid", PolicyName: "unused", Servers: []string{}, RuleIndex: -1,
+			Message: `policy "unused" is attached to no server`},
+	}, report.Findings)
+}
+
+func TestFirewallAuditor_Audit_managementPorts(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/fwpolicies/detail/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"objects":[{"name":"db","uuid":"db-uuid","servers":[{"uuid":"server-1"}],"rules":[`+
+			`{"action":"accept","direction":"in","ip_proto":"tcp","dst_port":"5432","dst_ip":"192.0.2.1"},`+
+			`{"action":"drop","direction":"in","ip_proto":"tcp"}]}]}`)
+	})
+	mux.HandleFunc("/servers/detail/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"objects":[]}`)
+	})
+
+	report, err := NewFirewallAuditor(client, &FirewallAuditOptions{ManagementPorts: []uint16{22, 5432}}).Audit(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []FirewallFinding{
+		{Severity: SeverityHigh, Kind: FirewallFindingManagementPortOpen, PolicyUUID: "db-uuid", PolicyName: "db", Servers: []string{"server-1"}, RuleIndex: 0,
+			Message: "port 5432 is open to 0.0.0.0/0 by rule 0"},
+	}, report.Findings)
+}
+
+func TestFirewallAuditReport_MaxSeverity(t *testing.T) {
+	assert.Equal(t, Severity(""), (&FirewallAuditReport{}).MaxSeverity())
+	assert.Equal(t, SeverityMedium, (&FirewallAuditReport{Findings: []FirewallFinding{
+		{Severity: SeverityLow}, {Severity: SeverityMedium},
+	}}).MaxSeverity())
+}
@@ -0,0 +1,279 @@
+package cloudsigma
+
+import (
+	"fmt"
+	"net/netip"
+)
+
+// FirewallDefaultAction is the action applied by CloudSigma to packets not
+// matched by any rule of a firewall policy.
+const FirewallDefaultAction = FirewallActionAccept
+
+// FirewallPacket describes a packet for EvaluateFirewallPolicy. Both IP
+// addresses must be of the same family, IPv4 or IPv6. Protocol is empty for
+// protocols other than TCP and UDP, e.g. ICMP, which have no ports.
+type FirewallPacket struct {
+	Direction       FirewallDirection
+	Protocol        FirewallProtocol
+	SourceIP        netip.Addr
+	SourcePort      uint16
+	DestinationIP   netip.Addr
+	DestinationPort uint16
+}
+
+// FirewallVerdict represents the result of evaluating a packet against a
+// firewall policy. RuleIndex is the index of the first matching rule, or -1
+// if no rule matches and FirewallDefaultAction applies.
+type FirewallVerdict struct {
+	Action    FirewallAction
+	RuleIndex int
+	Rule      *FirewallPolicyRule
+}
+
+// FirewallLintKind represents the kind of problem found by
+// LintFirewallPolicy.
+type FirewallLintKind string
+
+// Firewall lint kinds.
+const (
+	// FirewallLintInvalid reports a rule which cannot be parsed.
+	FirewallLintInvalid FirewallLintKind = "invalid"
+	// FirewallLintUnreachable reports a rule which matches no packet, e.g.
+	// because it mixes IPv4 and IPv6 addresses.
+	FirewallLintUnreachable FirewallLintKind = "unreachable"
+	// FirewallLintShadowed reports a rule whose packets are all matched by
+	// an earlier rule with a different action.
+	FirewallLintShadowed FirewallLintKind = "shadowed"
+	// FirewallLintRedundant reports a rule whose packets are all matched by
+	// an earlier rule with the same action.
+	FirewallLintRedundant FirewallLintKind = "redundant"
+)
+
+// FirewallLintIssue represents a problem of a single firewall policy rule.
+// CoveredBy is the index of the earlier rule for shadowed and redundant rules,
+// and -1 otherwise.
+type FirewallLintIssue struct {
+	Kind      FirewallLintKind
+	RuleIndex int
+	CoveredBy int
+	Message   string
+}
+
+// EvaluateFirewallPolicy returns the verdict of the policy for the packet,
+// applying the action of the first matching rule in order. An error is
+// returned if the packet or a rule before the matching one is invalid.
+func EvaluateFirewallPolicy(policy *FirewallPolicy, packet FirewallPacket) (*FirewallVerdict, error) {
+	if policy == nil {
+		return nil, ErrEmptyArgument
+	}
+	if err := validateFirewallPacket(&packet); err != nil {
+		return nil, err
+	}
+
+	for i := range policy.Rules {
+		m, err := compileFirewallRule(&policy.Rules[i])
+		if err != nil {
+			return nil, fmt.Errorf("cloudsigma-sdk-go: firewall rule %d: %w", i, err)
+		}
+		if m.matches(&packet) {
+			return &FirewallVerdict{Action: policy.Rules[i].Action, RuleIndex: i, Rule: &policy.Rules[i]}, nil
+		}
+	}
+	return &FirewallVerdict{Action: FirewallDefaultAction, RuleIndex: -1}, nil
+}
+
+// LintFirewallPolicy reports invalid and unreachable rules, and rules which
+// can never match because every packet they match is matched by a single
+// earlier rule. Issues are ordered by rule index.
+func LintFirewallPolicy(policy *FirewallPolicy) []FirewallLintIssue {
+	if policy == nil {
+		return nil
+	}
+
+	var issues []FirewallLintIssue
+	matchers := make([]*firewallMatcher, len(policy.Rules))
+	for j := range policy.Rules {
+		m, err := compileFirewallRule(&policy.Rules[j])
+		if err != nil {
+			issues = append(issues, FirewallLintIssue{Kind: FirewallLintInvalid, RuleIndex: j, CoveredBy: -1, Message: err.Error()})
+			continue
+		}
+		if m.src != nil && m.dst != nil && m.src.Addr().Is4() != m.dst.Addr().Is4() {
+			issues = append(issues, FirewallLintIssue{
+				Kind:      FirewallLintUnreachable,
+				RuleIndex: j,
+				CoveredBy: -1,
+				Message:   "source and destination addresses are of different IP families",
+			})
+			continue
+		}
+		matchers[j] = m
+
+		for i := 0; i < j; i++ {
+			if matchers[i] == nil || !matchers[i].covers(m) {
+				continue
+			}
+			issue := FirewallLintIssue{Kind: FirewallLintRedundant, RuleIndex: j, CoveredBy: i}
+			if policy.Rules[i].Action == policy.Rules[j].Action {
+				issue.Message = fmt.Sprintf("all packets are already matched by rule %d", i)
+			} else {
+				issue.Kind = FirewallLintShadowed
+				issue.Message = fmt.Sprintf("all packets are matched by rule %d, which %vs them", i, policy.Rules[i].Action)
+			}
+			issues = append(issues, issue)
+			break
+		}
+	}
+	return issues
+}
+
+func validateFirewallPacket(packet *FirewallPacket) error {
+	if !packet.Direction.IsValid() {
+		return fmt.Errorf("cloudsigma-sdk-go: packet direction must be in or out, got %q", packet.Direction)
+	}
+	if !packet.Protocol.IsValid() {
+		return fmt.Errorf("cloudsigma-sdk-go: packet protocol must be tcp, udp or empty, got %q", packet.Protocol)
+	}
+	if !packet.SourceIP.IsValid() || !packet.DestinationIP.IsValid() {
+		return fmt.Errorf("cloudsigma-sdk-go: packet source and destination IP are required")
+	}
+	packet.SourceIP = packet.SourceIP.Unmap()
+	packet.DestinationIP = packet.DestinationIP.Unmap()
+	if packet.SourceIP.Is4() != packet.DestinationIP.Is4() {
+		return fmt.Errorf("cloudsigma-sdk-go: packet source and destination IP are of different IP families")
+	}
+	return nil
+}
+
+// firewallMatcher is the parsed form of a firewall rule. Nil addresses and
+// ports match everything.
+type firewallMatcher struct {
+	direction FirewallDirection
+	protocol  FirewallProtocol
+	src, dst  *netip.Prefix
+	srcPorts  []firewallPortRange
+	dstPorts  []firewallPortRange
+}
+
+func compileFirewallRule(rule *FirewallPolicyRule) (*firewallMatcher, error) {
+	if err := validateFirewallRule(rule); err != nil {
+		return nil, err
+	}
+
+	m := &firewallMatcher{direction: rule.Direction, protocol: rule.Protocol}
+	var err error
+	if m.src, err = parseFirewallPrefix(rule.SourceIP); err != nil {
+		return nil, err
+	}
+	if m.dst, err = parseFirewallPrefix(rule.DestinationIP); err != nil {
+		return nil, err
+	}
+	if rule.SourcePort != "" {
+		if m.srcPorts, err = parseFirewallPorts(rule.SourcePort); err != nil {
+			return nil, err
+		}
+	}
+	if rule.DestinationPort != "" {
+		if m.dstPorts, err = parseFirewallPorts(rule.DestinationPort); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// parseFirewallPrefix parses a validated rule address. Single IP addresses
+// are returned as a prefix of full length.
+func parseFirewallPrefix(address string) (*netip.Prefix, error) {
+	if address == "" {
+		return nil, nil
+	}
+	prefix, err := netip.ParsePrefix(address)
+	if err != nil {
+		addr, err := netip.ParseAddr(address)
+		if err != nil {
+			return nil, err
+		}
+		addr = addr.Unmap()
+		prefix = netip.PrefixFrom(addr, addr.BitLen())
+	}
+	prefix = prefix.Masked()
+	return &prefix, nil
+}
+
+func (m *firewallMatcher) matches(packet *FirewallPacket) bool {
+	if m.direction != packet.Direction {
+		return false
+	}
+	if m.protocol != "" && m.protocol != packet.Protocol {
+		return false
+	}
+	if m.src != nil && !m.src.Contains(packet.SourceIP) {
+		return false
+	}
+	if m.dst != nil && !m.dst.Contains(packet.DestinationIP) {
+		return false
+	}
+	return portsContain(m.srcPorts, packet.SourcePort) && portsContain(m.dstPorts, packet.DestinationPort)
+}
+
+// covers reports whether every packet matched by other is matched by m.
+func (m *firewallMatcher) covers(other *firewallMatcher) bool {
+	if m.direction != other.direction {
+		return false
+	}
+	if m.protocol != "" && m.protocol != other.protocol {
+		return false
+	}
+	return prefixCovers(m.src, other.src) && prefixCovers(m.dst, other.dst) &&
+		portsCover(m.srcPorts, other.srcPorts) && portsCover(m.dstPorts, other.dstPorts)
+}
+
+func prefixCovers(p, other *netip.Prefix) bool {
+	if p == nil {
+		return true
+	}
+	if other == nil {
+		return false
+	}
+	return p.Bits() <= other.Bits() && p.Contains(other.Addr())
+}
+
+func portsContain(ranges []firewallPortRange, port uint16) bool {
+	if ranges == nil {
+		return true
+	}
+	for _, r := range ranges {
+		if port >= r.From && port <= r.To {
+			return true
+		}
+	}
+	return false
+}
+
+// portsCover reports whether every port in other is in ranges. Nil ranges
+// contain all ports.
+func portsCover(ranges, other []firewallPortRange) bool {
+	if ranges == nil {
+		return true
+	}
+	if other == nil {
+		other = []firewallPortRange{{From: 1, To: 65535}}
+	}
+	for _, r := range other {
+		// Walk the ports of r through the ranges containing them.
+		for port := int(r.From); port <= int(r.To); {
+			next := port
+			for _, c := range ranges {
+				if port >= int(c.From) && port <= int(c.To) {
+					next = int(c.To) + 1
+					break
+				}
+			}
+			if next == port {
+				return false
+			}
+			port = next
+		}
+	}
+	return true
+}
@@ -0,0 +1,140 @@
+package cloudsigma
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluateFirewallPolicy(t *testing.T) {
+	policy := &FirewallPolicy{Rules: mustParseFirewallRules(t, `
+in accept tcp from 10.0.0.0/8 to any port 5432 # internal postgres
+in drop tcp to any port 5432
+in accept udp from 2001:db8::/32 to any port 53,5000:5010
+in drop from 198.51.100.7
+out drop tcp to any port 25
+`)}
+	tests := []struct {
+		name      string
+		packet    FirewallPacket
+		action    FirewallAction
+		ruleIndex int
+	}{
+		{
+			name: "internal postgres",
+			packet: FirewallPacket{Direction: FirewallDirectionIn, Protocol: FirewallProtocolTCP,
+				SourceIP: netip.MustParseAddr("10.1.2.3"), SourcePort: 40000,
+				DestinationIP: netip.MustParseAddr("192.0.2.1"), DestinationPort: 5432},
+			action:    FirewallActionAccept,
+			ruleIndex: 0,
+		},
+		{
+			name: "external postgres",
+			packet: FirewallPacket{Direction: FirewallDirectionIn, Protocol: FirewallProtocolTCP,
+				SourceIP: netip.MustParseAddr("203.0.113.5"), SourcePort: 40000,
+				DestinationIP: netip.MustParseAddr("192.0.2.1"), DestinationPort: 5432},
+			action:    FirewallActionDrop,
+			ruleIndex: 1,
+		},
+		{
+			name: "IPv6 port range",
+			packet: FirewallPacket{Direction: FirewallDirectionIn, Protocol: FirewallProtocolUDP,
+				SourceIP: netip.MustParseAddr("2001:db8::5"), SourcePort: 40000,
+				DestinationIP: netip.MustParseAddr("2001:db8:1::1"), DestinationPort: 5005},
+			action:    FirewallActionAccept,
+			ruleIndex: 2,
+		},
+		{
+			name: "IPv4 mapped ICMP",
+			packet: FirewallPacket{Direction: FirewallDirectionIn,
+				SourceIP:      netip.MustParseAddr("::ffff:198.51.100.7"),
+				DestinationIP: netip.MustParseAddr("192.0.2.1")},
+			action:    FirewallActionDrop,
+			ruleIndex: 3,
+		},
+		{
+			name: "outgoing smtp",
+			packet: FirewallPacket{Direction: FirewallDirectionOut, Protocol: FirewallProtocolTCP,
+				SourceIP: netip.MustParseAddr("192.0.2.1"), SourcePort: 40000,
+				DestinationIP: netip.MustParseAddr("203.0.113.5"), DestinationPort: 25},
+			action:    FirewallActionDrop,
+			ruleIndex: 4,
+		},
+		{
+			name: "default",
+			packet: FirewallPacket{Direction: FirewallDirectionOut, Protocol: FirewallProtocolTCP,
+				SourceIP: netip.MustParseAddr("192.0.2.1"), SourcePort: 40000,
+				DestinationIP: netip.MustParseAddr("203.0.113.5"), DestinationPort: 443},
+			action:    FirewallDefaultAction,
+			ruleIndex: -1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verdict, err := EvaluateFirewallPolicy(policy, tt.packet)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.action, verdict.Action)
+			assert.Equal(t, tt.ruleIndex, verdict.RuleIndex)
+			if tt.ruleIndex >= 0 {
+				assert.Equal(t, &policy.Rules[tt.ruleIndex], verdict.Rule)
+			}
+		})
+	}
+}
+
+func TestEvaluateFirewallPolicy_invalid(t *testing.T) {
+	packet := FirewallPacket{Direction: FirewallDirectionIn,
+		SourceIP: netip.MustParseAddr("10.0.0.1"), DestinationIP: netip.MustParseAddr("10.0.0.2")}
+
+	_, err := EvaluateFirewallPolicy(nil, packet)
+	assert.ErrorIs(t, err, ErrEmptyArgument)
+
+	_, err = EvaluateFirewallPolicy(&FirewallPolicy{}, FirewallPacket{Direction: FirewallDirectionIn})
+	assert.EqualError(t, err, "cloudsigma-sdk-go: packet source and destination IP are required")
+
+	ipv6 := packet
+	ipv6.DestinationIP = netip.MustParseAddr("2001:db8::1")
+	_, err = EvaluateFirewallPolicy(&FirewallPolicy{}, ipv6)
+	assert.EqualError(t, err, "cloudsigma-sdk-go: packet source and destination IP are of different IP families")
+
+	policy := &FirewallPolicy{Rules: []FirewallPolicyRule{{Action: "reject", Direction: FirewallDirectionIn}}}
+	_, err = EvaluateFirewallPolicy(policy, packet)
+	assert.EqualError(t, err, `cloudsigma-sdk-go: firewall rule 0: action must be accept or drop, got "reject"`)
+}
+
+func TestLintFirewallPolicy(t *testing.T) {
+	rules := mustParseFirewallRules(t, `
+in accept tcp from 10.0.0.0/8 to any port 22,80:90
+in accept tcp from 10.1.0.0/16 to any port 85
+in drop tcp from 10.2.3.4 to any port 22
+in drop from 10.0.0.1 to 2001:db8::1
+in drop udp from 10.0.0.0/8
+in accept udp from 10.0.0.0/8 to any port 53
+in accept from any
+out drop tcp
+`)
+	rules = append(rules, FirewallPolicyRule{Action: FirewallActionAccept, Direction: FirewallDirectionIn, SourceIP: "10.0.0.0/40"})
+
+	issues := LintFirewallPolicy(&FirewallPolicy{Rules: rules})
+
+	assert.Equal(t, []FirewallLintIssue{
+		{Kind: FirewallLintRedundant, RuleIndex: 1, CoveredBy: 0, Message: "all packets are already matched by rule 0"},
+		{Kind: FirewallLintShadowed, RuleIndex: 2, CoveredBy: 0, Message: "all packets are matched by rule 0, which accepts them"},
+		{Kind: FirewallLintUnreachable, RuleIndex: 3, CoveredBy: -1, Message: "source and destination addresses are of different IP families"},
+		{Kind: FirewallLintShadowed, RuleIndex: 5, CoveredBy: 4, Message: "all packets are matched by rule 4, which drops them"},
+		{Kind: FirewallLintInvalid, RuleIndex: 8, CoveredBy: -1, Message: `invalid subnet "10.0.0.0/40"`},
+	}, issues)
+	assert.Nil(t, LintFirewallPolicy(nil))
+}
+
+func TestPortsCover(t *testing.T) {
+	ranges := []firewallPortRange{{From: 1, To: 100}, {From: 101, To: 200}, {From: 300, To: 300}}
+
+	assert.True(t, portsCover(nil, ranges))
+	assert.True(t, portsCover(ranges, []firewallPortRange{{From: 50, To: 150}, {From: 300, To: 300}}))
+	assert.False(t, portsCover(ranges, []firewallPortRange{{From: 150, To: 250}}))
+	assert.False(t, portsCover(ranges, nil))
+	assert.True(t, portsCover([]firewallPortRange{{From: 1, To: 65535}}, nil))
+}
@@ -44,16 +44,61 @@ func (f *FirewallPolicy) MarshalJSON() ([]byte, error) {
 	return json.Marshal(a)
 }
 
+// FirewallAction represents the action of a firewall policy rule.
+type FirewallAction string
+
+// Firewall rule actions.
+const (
+	FirewallActionAccept FirewallAction = "accept"
+	FirewallActionDrop   FirewallAction = "drop"
+)
+
+// IsValid reports whether the action is known to the SDK.
+func (a FirewallAction) IsValid() bool {
+	return a == FirewallActionAccept || a == FirewallActionDrop
+}
+
+// FirewallDirection represents the traffic direction of a firewall policy
+// rule, seen from the server.
+type FirewallDirection string
+
+// Firewall rule directions.
+const (
+	FirewallDirectionIn  FirewallDirection = "in"
+	FirewallDirectionOut FirewallDirection = "out"
+)
+
+// IsValid reports whether the direction is known to the SDK.
+func (d FirewallDirection) IsValid() bool {
+	return d == FirewallDirectionIn || d == FirewallDirectionOut
+}
+
+// FirewallProtocol represents the IP protocol of a firewall policy rule. An
+// empty protocol matches all protocols.
+type FirewallProtocol string
+
+// Firewall rule protocols.
+const (
+	FirewallProtocolTCP FirewallProtocol = "tcp"
+	FirewallProtocolUDP FirewallProtocol = "udp"
+)
+
+// IsValid reports whether the protocol is known to the SDK. The empty
+// protocol is valid.
+func (p FirewallProtocol) IsValid() bool {
+	return p == "" || p == FirewallProtocolTCP || p == FirewallProtocolUDP
+}
+
 // FirewallPolicyRule represents a CloudSigma firewall policy rule.
 type FirewallPolicyRule struct {
-	Action          string `json:"action,omitempty"`
-	Comment         string `json:"comment,omitempty"`
-	Direction       string `json:"direction,omitempty"`
-	DestinationIP   string `json:"dst_ip,omitempty"`
-	DestinationPort string `json:"dst_port,omitempty"`
-	Protocol        string `json:"ip_proto,omitempty"`
-	SourceIP        string `json:"src_ip,omitempty"`
-	SourcePort      string `json:"src_port,omitempty"`
+	Action          FirewallAction    `json:"action,omitempty"`
+	Comment         string            `json:"comment,omitempty"`
+	Direction       FirewallDirection `json:"direction,omitempty"`
+	DestinationIP   string            `json:"dst_ip,omitempty"`
+	DestinationPort string            `json:"dst_port,omitempty"`
+	Protocol        FirewallProtocol  `json:"ip_proto,omitempty"`
+	SourceIP        string            `json:"src_ip,omitempty"`
+	SourcePort      string            `json:"src_port,omitempty"`
 }
 
 // FirewallPolicyCreateRequest represents a request to create a firewall policy.
@@ -79,8 +124,12 @@ func (f FirewallPolicy) String() string {
 // user has access.
 //
 // CloudSigma API docs: https://cloudsigma-docs.readthedocs.io/en/latest/fwpolicies.html#detailed-listing
-func (s *FirewallPoliciesService) List(ctx context.Context) ([]FirewallPolicy, *Response, error) {
+func (s *FirewallPoliciesService) List(ctx context.Context, opts *ListOptions) ([]FirewallPolicy, *Response, error) {
 	path := fmt.Sprintf("%v/detail/", fwpoliciesBasePath)
+	path, err := addOptions(path, opts)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	req, err := s.client.NewRequest(http.MethodGet, path, nil)
 	if err != nil {
@@ -23,7 +23,7 @@ func TestFirewallPolicies_List(t *testing.T) {
 		},
 	}
 
-	policies, resp, err := client.FirewallPolicies.List(ctx)
+	policies, resp, err := client.FirewallPolicies.List(ctx, nil)
 
 	assert.NoError(t, err)
 	assert.Equal(t, expected, policies)
@@ -173,3 +173,13 @@ func TestFirewallPolicies_Delete_emptyUUID(t *testing.T) {
 	assert.Error(t, err)
 	assert.Equal(t, ErrEmptyArgument.Error(), err.Error())
 }
+
+func TestFirewallPolicyRule_enums(t *testing.T) {
+	assert.True(t, FirewallActionAccept.IsValid())
+	assert.False(t, FirewallAction("reject").IsValid())
+	assert.True(t, FirewallDirectionOut.IsValid())
+	assert.False(t, FirewallDirection("both").IsValid())
+	assert.True(t, FirewallProtocol("").IsValid())
+	assert.True(t, FirewallProtocolUDP.IsValid())
+	assert.False(t, FirewallProtocol("icmp").IsValid())
+}
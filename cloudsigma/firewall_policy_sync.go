@@ -0,0 +1,321 @@
+package cloudsigma
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// FirewallPolicySyncOptions specifies the optional parameters to the
+// FirewallPoliciesService.Sync.
+type FirewallPolicySyncOptions struct {
+	// DryRun computes the diff without creating or updating the policy.
+	DryRun bool
+}
+
+// FirewallRuleChangeType represents the kind of change of a firewall rule.
+type FirewallRuleChangeType string
+
+// Firewall rule change types.
+const (
+	FirewallRuleAdded   FirewallRuleChangeType = "added"
+	FirewallRuleRemoved FirewallRuleChangeType = "removed"
+	FirewallRuleMoved   FirewallRuleChangeType = "moved"
+)
+
+// FirewallRuleChange represents a change of a single firewall policy rule.
+// OldIndex is -1 for added rules, and NewIndex is -1 for removed rules.
+type FirewallRuleChange struct {
+	Type     FirewallRuleChangeType
+	Rule     FirewallPolicyRule
+	OldIndex int
+	NewIndex int
+}
+
+// FirewallPolicyDiff represents the changes needed to turn an existing
+// firewall policy into the desired one.
+type FirewallPolicyDiff struct {
+	// Create is set if no policy matched and the desired policy is created.
+	Create bool
+	// Rules lists removed rules ordered by their old index, followed by
+	// added and moved rules ordered by their new index.
+	Rules []FirewallRuleChange
+	// AttachServers and DetachServers list server UUIDs, sorted.
+	AttachServers []string
+	DetachServers []string
+	// Fields lists other changed fields by their JSON name, i.e. "name",
+	// "meta" and "tags".
+	Fields []string
+}
+
+// FirewallPolicySyncResult represents the result of a
+// FirewallPoliciesService.Sync.
+type FirewallPolicySyncResult struct {
+	Diff FirewallPolicyDiff
+	// Policy is the created or updated policy. In a dry run it is the
+	// matched policy, or nil if the policy would be created.
+	Policy *FirewallPolicy
+}
+
+// IsEmpty reports whether the diff has no changes.
+func (d *FirewallPolicyDiff) IsEmpty() bool {
+	return !d.Create && len(d.Rules) == 0 && len(d.AttachServers) == 0 && len(d.DetachServers) == 0 && len(d.Fields) == 0
+}
+
+// String returns the diff one change per line, e.g.
+//
+//	~ name
+//	+ [1] in drop from any to any
+//	~ [2 -> 0] in accept tcp from any to any port 22
+//	- [3] out accept from any to any
+//	+ server 43b1110a-31c5-4cc3-8ede-7f4d8d8c2b7d
+func (d *FirewallPolicyDiff) String() string {
+	var lines []string
+	if d.Create {
+		lines = append(lines, "+ policy")
+	}
+	for _, f := range d.Fields {
+		lines = append(lines, "~ "+f)
+	}
+	for _, c := range d.Rules {
+		switch c.Type {
+		case FirewallRuleAdded:
+			lines = append(lines, fmt.Sprintf("+ [%d] %v", c.NewIndex, formatFirewallRule(&c.Rule)))
+		case FirewallRuleRemoved:
+			lines = append(lines, fmt.Sprintf("- [%d] %v", c.OldIndex, formatFirewallRule(&c.Rule)))
+		case FirewallRuleMoved:
+			lines = append(lines, fmt.Sprintf("~ [%d -> %d] %v", c.OldIndex, c.NewIndex, formatFirewallRule(&c.Rule)))
+		}
+	}
+	for _, uuid := range d.AttachServers {
+		lines = append(lines, "+ server "+uuid)
+	}
+	for _, uuid := range d.DetachServers {
+		lines = append(lines, "- server "+uuid)
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// Sync makes an existing firewall policy match the desired one with a single
+// Update, or creates it if none matches. The policy is matched by the UUID of
+// desired if set, and by its name otherwise.
+//
+// The desired rules replace the existing ones. Servers, Tags and Meta are only
+// changed if they are not nil in desired, so an empty slice detaches all
+// servers or removes all tags. Nothing is sent if the diff is empty, or if
+// opts.DryRun is set.
+func (s *FirewallPoliciesService) Sync(ctx context.Context, desired *FirewallPolicy, opts *FirewallPolicySyncOptions) (*FirewallPolicySyncResult, *Response, error) {
+	if desired == nil {
+		return nil, nil, ErrEmptyPayloadNotAllowed
+	}
+	if desired.UUID == "" && desired.Name == "" {
+		return nil, nil, ErrEmptyArgument
+	}
+	if opts == nil {
+		opts = &FirewallPolicySyncOptions{}
+	}
+
+	existing, resp, err := s.match(ctx, desired)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if existing == nil {
+		result := &FirewallPolicySyncResult{Diff: diffFirewallPolicy(&FirewallPolicy{}, desired)}
+		result.Diff.Create = true
+		if opts.DryRun {
+			return result, nil, nil
+		}
+		createRequest := &FirewallPolicyCreateRequest{FirewallPolicies: []FirewallPolicy{*desired}}
+		policies, resp, err := s.Create(ctx, createRequest)
+		if err != nil {
+			return nil, resp, err
+		}
+		if len(policies) == 0 {
+			return nil, resp, ErrResourceNotFound
+		}
+		result.Policy = &policies[0]
+		return result, resp, nil
+	}
+
+	result := &FirewallPolicySyncResult{Diff: diffFirewallPolicy(existing, desired), Policy: existing}
+	if opts.DryRun || result.Diff.IsEmpty() {
+		return result, resp, nil
+	}
+
+	updated := *existing
+	updated.Rules = desired.Rules
+	if desired.Name != "" {
+		updated.Name = desired.Name
+	}
+	if desired.Meta != nil {
+		updated.Meta = desired.Meta
+	}
+	if desired.Servers != nil {
+		updated.Servers = desired.Servers
+	}
+	if desired.Tags != nil {
+		updated.Tags = desired.Tags
+	}
+	policy, resp, err := s.Update(ctx, existing.UUID, &FirewallPolicyUpdateRequest{FirewallPolicy: &updated})
+	if err != nil {
+		return nil, resp, err
+	}
+	result.Policy = policy
+	return result, resp, nil
+}
+
+// match returns the existing policy with the UUID or name of desired, or nil
+// if there is none. Only one policy may have the name, so all policies are
+// listed instead of the first page.
+func (s *FirewallPoliciesService) match(ctx context.Context, desired *FirewallPolicy) (*FirewallPolicy, *Response, error) {
+	if desired.UUID != "" {
+		return s.Get(ctx, desired.UUID)
+	}
+
+	policies, resp, err := s.List(ctx, &ListOptions{Limit: 0})
+	if err != nil {
+		return nil, resp, err
+	}
+	var matched *FirewallPolicy
+	for i := range policies {
+		if policies[i].Name != desired.Name {
+			continue
+		}
+		if matched != nil {
+			return nil, resp, fmt.Errorf("cloudsigma-sdk-go: more than one firewall policy is named %q", desired.Name)
+		}
+		matched = &policies[i]
+	}
+	return matched, resp, nil
+}
+
+func diffFirewallPolicy(existing, desired *FirewallPolicy) FirewallPolicyDiff {
+	var diff FirewallPolicyDiff
+	if desired.Name != "" && desired.Name != existing.Name {
+		diff.Fields = append(diff.Fields, "name")
+	}
+	if desired.Meta != nil && !reflect.DeepEqual(desired.Meta, existing.Meta) {
+		diff.Fields = append(diff.Fields, "meta")
+	}
+	if desired.Tags != nil {
+		added, removed := diffResourceUUIDs(tagUUIDs(existing.Tags), tagUUIDs(desired.Tags))
+		if len(added) > 0 || len(removed) > 0 {
+			diff.Fields = append(diff.Fields, "tags")
+		}
+	}
+	diff.Rules = diffFirewallRules(existing.Rules, desired.Rules)
+	if desired.Servers != nil {
+		diff.AttachServers, diff.DetachServers = diffResourceUUIDs(linkUUIDs(existing.Servers), linkUUIDs(desired.Servers))
+	}
+	return diff
+}
+
+// diffFirewallRules computes the ordered changes from before to after rules.
+// Rules in the longest common subsequence are unchanged, other equal rules
+// are paired as moved, and the rest are removed or added.
+func diffFirewallRules(before, after []FirewallPolicyRule) []FirewallRuleChange {
+	// lcs[i][j] is the length of the longest common subsequence of before[i:]
+	// and after[j:].
+	lcs := make([][]int, len(before)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(after)+1)
+	}
+	for i := len(before) - 1; i >= 0; i-- {
+		for j := len(after) - 1; j >= 0; j-- {
+			if before[i] == after[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var removed, added []int
+	for i, j := 0, 0; i < len(before) || j < len(after); {
+		switch {
+		case i < len(before) && j < len(after) && before[i] == after[j]:
+			i++
+			j++
+		case j == len(after) || (i < len(before) && lcs[i+1][j] >= lcs[i][j+1]):
+			removed = append(removed, i)
+			i++
+		default:
+			added = append(added, j)
+			j++
+		}
+	}
+
+	// Pair removed and added equal rules as moved.
+	movedFrom := make(map[int]int)
+	pairedOld := make(map[int]bool)
+	for _, j := range added {
+		for _, i := range removed {
+			if !pairedOld[i] && before[i] == after[j] {
+				pairedOld[i] = true
+				movedFrom[j] = i
+				break
+			}
+		}
+	}
+
+	var changes []FirewallRuleChange
+	for _, i := range removed {
+		if !pairedOld[i] {
+			changes = append(changes, FirewallRuleChange{Type: FirewallRuleRemoved, Rule: before[i], OldIndex: i, NewIndex: -1})
+		}
+	}
+	for _, j := range added {
+		if i, ok := movedFrom[j]; ok {
+			changes = append(changes, FirewallRuleChange{Type: FirewallRuleMoved, Rule: after[j], OldIndex: i, NewIndex: j})
+		} else {
+			changes = append(changes, FirewallRuleChange{Type: FirewallRuleAdded, Rule: after[j], OldIndex: -1, NewIndex: j})
+		}
+	}
+	return changes
+}
+
+// diffResourceUUIDs returns the sorted UUIDs only in desired, and only in
+// existing.
+func diffResourceUUIDs(existing, desired []string) (added, removed []string) {
+	existingSet := make(map[string]bool, len(existing))
+	for _, uuid := range existing {
+		existingSet[uuid] = true
+	}
+	desiredSet := make(map[string]bool, len(desired))
+	for _, uuid := range desired {
+		desiredSet[uuid] = true
+		if !existingSet[uuid] {
+			added = append(added, uuid)
+		}
+	}
+	for _, uuid := range existing {
+		if !desiredSet[uuid] {
+			removed = append(removed, uuid)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
+
+func linkUUIDs(links []ResourceLink) []string {
+	uuids := make([]string, 0, len(links))
+	for _, l := range links {
+		uuids = append(uuids, l.UUID)
+	}
+	return uuids
+}
+
+func tagUUIDs(tags []Tag) []string {
+	uuids := make([]string, 0, len(tags))
+	for _, t := range tags {
+		uuids = append(uuids, t.UUID)
+	}
+	return uuids
+}
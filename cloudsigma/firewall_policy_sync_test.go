@@ -0,0 +1,204 @@
+package cloudsigma
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mustParseFirewallRules(t *testing.T, text string) []FirewallPolicyRule {
+	t.Helper()
+	rules, err := ParseFirewallRules(text)
+	assert.NoError(t, err)
+	return rules
+}
+
+func TestDiffFirewallRules(t *testing.T) {
+	before := mustParseFirewallRules(t, `
+in accept tcp to any port 22
+in accept tcp to any port 80
+in accept tcp to any port 443
+in drop
+`)
+	after := mustParseFirewallRules(t, `
+in accept tcp to any port 443
+in accept tcp to any port 22
+in accept udp to any port 53
+in drop
+`)
+
+	changes := diffFirewallRules(before, after)
+
+	assert.Equal(t, []FirewallRuleChange{
+		{Type: FirewallRuleRemoved, Rule: before[1], OldIndex: 1, NewIndex: -1},
+		{Type: FirewallRuleMoved, Rule: before[0], OldIndex: 0, NewIndex: 1},
+		{Type: FirewallRuleAdded, Rule: after[2], OldIndex: -1, NewIndex: 2},
+	}, changes)
+}
+
+func TestDiffFirewallRules_equal(t *testing.T) {
+	rules := mustParseFirewallRules(t, "in accept tcp to any port 22\nin drop\n")
+
+	assert.Empty(t, diffFirewallRules(rules, rules))
+	assert.Empty(t, diffFirewallRules(nil, nil))
+}
+
+func TestFirewallPolicyDiff_String(t *testing.T) {
+	before := mustParseFirewallRules(t, "in drop\nin accept tcp to any port 22\n")
+	after := mustParseFirewallRules(t, "in accept tcp to any port 22\nout accept\n")
+	diff := diffFirewallPolicy(
+		&FirewallPolicy{Name: "old", Rules: before, Servers: []ResourceLink{{UUID: "server-1"}}},
+		&FirewallPolicy{Name: "web", Rules: after, Servers: []ResourceLink{{UUID: "server-2"}}},
+	)
+
+	assert.Equal(t, "~ name\n"+
+		"- [0] in drop from any to any\n"+
+		"+ [1] out accept from any to any\n"+
+		"+ server server-2\n"+
+		"- server server-1\n", diff.String())
+	assert.False(t, diff.IsEmpty())
+	assert.Equal(t, "", (&FirewallPolicyDiff{}).String())
+}
+
+func TestFirewallPolicies_Sync_dryRun(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/fwpolicies/detail/", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		_, _ = fmt.Fprint(w, `{"objects":[`+
+			`{"name":"db","uuid":"db-uuid","rules":[]},`+
+			`{"name":"web","uuid":"web-uuid","rules":[{"action":"drop","direction":"in"}],"servers":[{"uuid":"server-1"}]}`+
+			`],"meta":{"total_count":2}}`)
+	})
+	mux.HandleFunc("/fwpolicies/web-uuid/", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("policy must not be updated in a dry run")
+	})
+	desired := &FirewallPolicy{
+		Name:  "web",
+		Rules: mustParseFirewallRules(t, "in accept tcp to any port 443\nin drop\n"),
+	}
+
+	result, _, err := client.FirewallPolicies.Sync(ctx, desired, &FirewallPolicySyncOptions{DryRun: true})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "web-uuid", result.Policy.UUID)
+	assert.Equal(t, "+ [0] in accept tcp from any to any port 443\n", result.Diff.String())
+	assert.Empty(t, result.Diff.DetachServers)
+}
+
+func TestFirewallPolicies_Sync_update(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/fwpolicies/web-uuid/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			_, _ = fmt.Fprint(w, `{"name":"web","uuid":"web-uuid",`+
+				`"rules":[{"action":"drop","direction":"in"}],"servers":[{"uuid":"server-1"}],"tags":[{"uuid":"tag-1"}]}`)
+			return
+		}
+		assert.Equal(t, http.MethodPut, r.Method)
+		var body map[string]interface{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, []interface{}{}, body["rules"])
+		assert.Equal(t, []interface{}{}, body["servers"])
+		assert.Equal(t, []interface{}{map[string]interface{}{"uuid": "tag-1"}}, body["tags"])
+		_, _ = fmt.Fprint(w, `{"name":"web","uuid":"web-uuid","rules":[],"servers":[],"tags":[{"uuid":"tag-1"}]}`)
+	})
+	desired := &FirewallPolicy{UUID: "web-uuid", Servers: []ResourceLink{}}
+
+	result, _, err := client.FirewallPolicies.Sync(ctx, desired, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"server-1"}, result.Diff.DetachServers)
+	assert.Len(t, result.Diff.Rules, 1)
+	assert.Empty(t, result.Policy.Rules)
+	assert.Empty(t, result.Policy.Servers)
+}
+
+func TestFirewallPolicies_Sync_unchanged(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/fwpolicies/web-uuid/", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		_, _ = fmt.Fprint(w, `{"name":"web","uuid":"web-uuid","rules":[{"action":"drop","direction":"in"}]}`)
+	})
+	desired := &FirewallPolicy{UUID: "web-uuid", Rules: mustParseFirewallRules(t, "in drop\n")}
+
+	result, _, err := client.FirewallPolicies.Sync(ctx, desired, nil)
+
+	assert.NoError(t, err)
+	assert.True(t, result.Diff.IsEmpty())
+	assert.Equal(t, "web-uuid", result.Policy.UUID)
+}
+
+func TestFirewallPolicies_Sync_create(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/fwpolicies/detail/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"objects":[],"meta":{"total_count":0}}`)
+	})
+	mux.HandleFunc("/fwpolicies/", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		_, _ = fmt.Fprint(w, `{"objects":[{"name":"web","uuid":"web-uuid","rules":[{"action":"drop","direction":"in"}]}]}`)
+	})
+	desired := &FirewallPolicy{Name: "web", Rules: mustParseFirewallRules(t, "in drop\n")}
+
+	result, _, err := client.FirewallPolicies.Sync(ctx, desired, nil)
+
+	assert.NoError(t, err)
+	assert.True(t, result.Diff.Create)
+	assert.Equal(t, "+ policy\n~ name\n+ [0] in drop from any to any\n", result.Diff.String())
+	assert.Equal(t, "web-uuid", result.Policy.UUID)
+}
+
+func TestFirewallPolicies_Sync_paged(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/fwpolicies/detail/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("limit") != "0" {
+			_, _ = fmt.Fprint(w, `{"objects":[{"name":"db","uuid":"db-uuid"}],"meta":{"limit":1,"total_count":2}}`)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"objects":[{"name":"db","uuid":"db-uuid"},{"name":"web","uuid":"web-uuid","rules":[{"action":"drop","direction":"in"}]}],"meta":{"total_count":2}}`)
+	})
+	mux.HandleFunc("/fwpolicies/", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("policy on the second page must not be created again")
+	})
+	desired := &FirewallPolicy{Name: "web", Rules: mustParseFirewallRules(t, "in drop\n")}
+
+	result, _, err := client.FirewallPolicies.Sync(ctx, desired, nil)
+
+	assert.NoError(t, err)
+	assert.False(t, result.Diff.Create)
+	assert.Equal(t, "web-uuid", result.Policy.UUID)
+}
+
+func TestFirewallPolicies_Sync_ambiguousName(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/fwpolicies/detail/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"objects":[{"name":"web","uuid":"uuid-1"},{"name":"web","uuid":"uuid-2"}]}`)
+	})
+
+	_, _, err := client.FirewallPolicies.Sync(ctx, &FirewallPolicy{Name: "web"}, nil)
+
+	assert.EqualError(t, err, `cloudsigma-sdk-go: more than one firewall policy is named "web"`)
+}
+
+func TestFirewallPolicies_Sync_emptyPayload(t *testing.T) {
+	_, _, err := client.FirewallPolicies.Sync(ctx, nil, nil)
+
+	assert.ErrorIs(t, err, ErrEmptyPayloadNotAllowed)
+
+	_, _, err = client.FirewallPolicies.Sync(ctx, &FirewallPolicy{}, nil)
+
+	assert.ErrorIs(t, err, ErrEmptyArgument)
+}
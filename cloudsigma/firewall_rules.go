@@ -0,0 +1,287 @@
+package cloudsigma
+
+import (
+	"bufio"
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+// Keywords of the firewall rule syntax.
+const (
+	firewallKeywordAny  = "any"
+	firewallKeywordFrom = "from"
+	firewallKeywordTo   = "to"
+	firewallKeywordPort = "port"
+)
+
+// FirewallRuleSyntaxError reports a firewall rule which cannot be parsed or
+// formatted. Line is the 1-based line number for ParseFirewallRules, and 0
+// otherwise.
+type FirewallRuleSyntaxError struct {
+	Line    int
+	Rule    string
+	Message string
+}
+
+func (e *FirewallRuleSyntaxError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("cloudsigma-sdk-go: firewall rule on line %d: %v: %q", e.Line, e.Message, e.Rule)
+	}
+	return fmt.Sprintf("cloudsigma-sdk-go: firewall rule: %v: %q", e.Message, e.Rule)
+}
+
+// ParseFirewallRule parses a firewall policy rule written as
+//
+//	<in|out> <accept|drop> [tcp|udp|any] [from <addr> [port <ports>]] [to <addr> [port <ports>]] [# comment]
+//
+// An address is "any", an IP address or a CIDR subnet. Ports are a comma
+// separated list of ports and port ranges like "1000:2000", and require the
+// tcp or udp protocol. Omitted addresses and protocols match everything. For
+// example:
+//
+//	in accept tcp from 10.0.0.0/8 to any port 22,443 # ssh
+//
+// Values are kept as written, so a rule formatted with FormatFirewallRule is
+// parsed to the same FirewallPolicyRule. A *FirewallRuleSyntaxError is
+// returned if the rule is invalid.
+func ParseFirewallRule(s string) (FirewallPolicyRule, error) {
+	rule, err := parseFirewallRule(s)
+	if err != nil {
+		return FirewallPolicyRule{}, &FirewallRuleSyntaxError{Rule: strings.TrimSpace(s), Message: err.Error()}
+	}
+	return rule, nil
+}
+
+// ParseFirewallRules parses firewall policy rules written one per line, see
+// ParseFirewallRule. Empty lines and lines starting with "#" are skipped.
+func ParseFirewallRules(text string) ([]FirewallPolicyRule, error) {
+	var rules []FirewallPolicyRule
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for line := 1; scanner.Scan(); line++ {
+		s := strings.TrimSpace(scanner.Text())
+		if s == "" || strings.HasPrefix(s, "#") {
+			continue
+		}
+		rule, err := parseFirewallRule(s)
+		if err != nil {
+			return nil, &FirewallRuleSyntaxError{Line: line, Rule: s, Message: err.Error()}
+		}
+		rules = append(rules, rule)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// FormatFirewallRule returns the canonical text of a firewall policy rule,
+// e.g. "in accept tcp from 10.0.0.0/8 to any port 22,443 # ssh". The
+// protocol is omitted if empty, and both addresses are always written. A
+// *FirewallRuleSyntaxError is returned if the rule is invalid.
+func FormatFirewallRule(rule FirewallPolicyRule) (string, error) {
+	if err := validateFirewallRule(&rule); err != nil {
+		return "", &FirewallRuleSyntaxError{Rule: Stringify(rule), Message: err.Error()}
+	}
+	return formatFirewallRule(&rule), nil
+}
+
+// FormatFirewallRules returns the canonical text of firewall policy rules,
+// one rule per line. See FormatFirewallRule.
+func FormatFirewallRules(rules []FirewallPolicyRule) (string, error) {
+	var b strings.Builder
+	for i := range rules {
+		if err := validateFirewallRule(&rules[i]); err != nil {
+			return "", &FirewallRuleSyntaxError{Line: i + 1, Rule: Stringify(rules[i]), Message: err.Error()}
+		}
+		b.WriteString(formatFirewallRule(&rules[i]))
+		b.WriteString("\n")
+	}
+	return b.String(), nil
+}
+
+func formatFirewallRule(rule *FirewallPolicyRule) string {
+	fields := []string{string(rule.Direction), string(rule.Action)}
+	if rule.Protocol != "" {
+		fields = append(fields, string(rule.Protocol))
+	}
+	fields = append(fields, firewallKeywordFrom, formatFirewallAddress(rule.SourceIP))
+	if rule.SourcePort != "" {
+		fields = append(fields, firewallKeywordPort, rule.SourcePort)
+	}
+	fields = append(fields, firewallKeywordTo, formatFirewallAddress(rule.DestinationIP))
+	if rule.DestinationPort != "" {
+		fields = append(fields, firewallKeywordPort, rule.DestinationPort)
+	}
+	if rule.Comment != "" {
+		fields = append(fields, "#", rule.Comment)
+	}
+	return strings.Join(fields, " ")
+}
+
+func formatFirewallAddress(address string) string {
+	if address == "" {
+		return firewallKeywordAny
+	}
+	return address
+}
+
+func parseFirewallRule(s string) (FirewallPolicyRule, error) {
+	var rule FirewallPolicyRule
+	s, comment, _ := strings.Cut(s, "#")
+	rule.Comment = strings.TrimSpace(comment)
+
+	tokens := strings.Fields(s)
+	next := func() string {
+		if len(tokens) == 0 {
+			return ""
+		}
+		token := tokens[0]
+		tokens = tokens[1:]
+		return token
+	}
+	peek := func() string {
+		if len(tokens) == 0 {
+			return ""
+		}
+		return tokens[0]
+	}
+
+	rule.Direction = FirewallDirection(next())
+	if !rule.Direction.IsValid() {
+		return rule, fmt.Errorf("direction must be in or out, got %q", rule.Direction)
+	}
+	rule.Action = FirewallAction(next())
+	if !rule.Action.IsValid() {
+		return rule, fmt.Errorf("action must be accept or drop, got %q", rule.Action)
+	}
+	if p := peek(); p != "" && p != firewallKeywordFrom && p != firewallKeywordTo {
+		next()
+		if p != firewallKeywordAny {
+			rule.Protocol = FirewallProtocol(p)
+		}
+		if !rule.Protocol.IsValid() {
+			return rule, fmt.Errorf("protocol must be tcp, udp or any, got %q", p)
+		}
+	}
+
+	endpoint := func(keyword string) (address, port string, err error) {
+		if peek() != keyword {
+			return "", "", nil
+		}
+		next()
+		address = next()
+		if address == "" {
+			return "", "", fmt.Errorf("missing address after %q", keyword)
+		}
+		if address == firewallKeywordAny {
+			address = ""
+		}
+		if peek() == firewallKeywordPort {
+			next()
+			port = next()
+			if port == "" {
+				return "", "", fmt.Errorf("missing ports after %q", firewallKeywordPort)
+			}
+		}
+		return address, port, nil
+	}
+	var err error
+	if rule.SourceIP, rule.SourcePort, err = endpoint(firewallKeywordFrom); err != nil {
+		return rule, err
+	}
+	if rule.DestinationIP, rule.DestinationPort, err = endpoint(firewallKeywordTo); err != nil {
+		return rule, err
+	}
+	if len(tokens) > 0 {
+		return rule, fmt.Errorf("unexpected %q", tokens[0])
+	}
+
+	return rule, validateFirewallRule(&rule)
+}
+
+// validateFirewallRule checks the values of a firewall rule, so it can be
+// formatted and parsed back without changes.
+func validateFirewallRule(rule *FirewallPolicyRule) error {
+	if !rule.Direction.IsValid() {
+		return fmt.Errorf("direction must be in or out, got %q", rule.Direction)
+	}
+	if !rule.Action.IsValid() {
+		return fmt.Errorf("action must be accept or drop, got %q", rule.Action)
+	}
+	if !rule.Protocol.IsValid() {
+		return fmt.Errorf("protocol must be tcp, udp or empty, got %q", rule.Protocol)
+	}
+	for _, address := range []string{rule.SourceIP, rule.DestinationIP} {
+		if err := validateFirewallAddress(address); err != nil {
+			return err
+		}
+	}
+	for _, ports := range []string{rule.SourcePort, rule.DestinationPort} {
+		if ports == "" {
+			continue
+		}
+		if rule.Protocol == "" {
+			return fmt.Errorf("ports %q require the tcp or udp protocol", ports)
+		}
+		if _, err := parseFirewallPorts(ports); err != nil {
+			return err
+		}
+	}
+	if rule.Comment != strings.TrimSpace(rule.Comment) || strings.ContainsAny(rule.Comment, "\r\n") {
+		return fmt.Errorf("comment %q must be a single line without surrounding spaces", rule.Comment)
+	}
+	return nil
+}
+
+func validateFirewallAddress(address string) error {
+	if address == "" {
+		return nil
+	}
+	if strings.Contains(address, "/") {
+		if _, err := netip.ParsePrefix(address); err != nil {
+			return fmt.Errorf("invalid subnet %q", address)
+		}
+		return nil
+	}
+	if _, err := netip.ParseAddr(address); err != nil {
+		return fmt.Errorf("invalid IP address %q", address)
+	}
+	return nil
+}
+
+// firewallPortRange represents an inclusive range of ports.
+type firewallPortRange struct {
+	From, To uint16
+}
+
+// parseFirewallPorts parses a comma separated list of ports and port ranges
+// like "22,1000:2000".
+func parseFirewallPorts(ports string) ([]firewallPortRange, error) {
+	var ranges []firewallPortRange
+	for _, part := range strings.Split(ports, ",") {
+		from, to, isRange := strings.Cut(part, ":")
+		if !isRange {
+			to = from
+		}
+		first, err := parseFirewallPort(from)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ports %q", ports)
+		}
+		last, err := parseFirewallPort(to)
+		if err != nil || last < first {
+			return nil, fmt.Errorf("invalid ports %q", ports)
+		}
+		ranges = append(ranges, firewallPortRange{From: first, To: last})
+	}
+	return ranges, nil
+}
+
+func parseFirewallPort(s string) (uint16, error) {
+	port, err := strconv.ParseUint(s, 10, 16)
+	if err != nil || port == 0 {
+		return 0, fmt.Errorf("invalid port %q", s)
+	}
+	return uint16(port), nil
+}
@@ -0,0 +1,195 @@
+package cloudsigma
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFirewallRule(t *testing.T) {
+	rule, err := ParseFirewallRule("in accept tcp from 10.0.0.0/8 to any port 22,443 # ssh")
+
+	assert.NoError(t, err)
+	assert.Equal(t, FirewallPolicyRule{
+		Action:          FirewallActionAccept,
+		Comment:         "ssh",
+		Direction:       FirewallDirectionIn,
+		DestinationPort: "22,443",
+		Protocol:        FirewallProtocolTCP,
+		SourceIP:        "10.0.0.0/8",
+	}, rule)
+}
+
+func TestParseFirewallRule_short(t *testing.T) {
+	rule, err := ParseFirewallRule("  out drop  ")
+
+	assert.NoError(t, err)
+	assert.Equal(t, FirewallPolicyRule{Action: FirewallActionDrop, Direction: FirewallDirectionOut}, rule)
+
+	rule, err = ParseFirewallRule("out accept udp to 2001:db8::/32 port 1000:2000")
+
+	assert.NoError(t, err)
+	assert.Equal(t, FirewallPolicyRule{
+		Action:          FirewallActionAccept,
+		Direction:       FirewallDirectionOut,
+		DestinationIP:   "2001:db8::/32",
+		DestinationPort: "1000:2000",
+		Protocol:        FirewallProtocolUDP,
+	}, rule)
+}
+
+func TestParseFirewallRule_invalid(t *testing.T) {
+	tests := map[string]string{
+		"":                                "direction must be in or out",
+		"inbound accept":                  "direction must be in or out",
+		"in allow":                        "action must be accept or drop",
+		"in accept icmp":                  "protocol must be tcp, udp or any",
+		"in accept tcp from":              `missing address after "from"`,
+		"in accept tcp from 10.0.0.0/33":  `invalid subnet "10.0.0.0/33"`,
+		"in accept tcp to 10.0.0.256":     `invalid IP address "10.0.0.256"`,
+		"in accept tcp to any port":       `missing ports after "port"`,
+		"in accept tcp to any port 0":     `invalid ports "0"`,
+		"in accept tcp to any port 70000": `invalid ports "70000"`,
+		"in accept tcp to any port 22:10": `invalid ports "22:10"`,
+		"in accept tcp to any port 22,":   `invalid ports "22,"`,
+		"in accept to any port 22":        `ports "22" require the tcp or udp protocol`,
+		"in accept tcp to any from any":   `unexpected "from"`,
+	}
+	for text, message := range tests {
+		t.Run(text, func(t *testing.T) {
+			_, err := ParseFirewallRule(text)
+
+			var syntaxErr *FirewallRuleSyntaxError
+			if assert.ErrorAs(t, err, &syntaxErr) {
+				assert.Contains(t, syntaxErr.Message, message)
+				assert.Equal(t, 0, syntaxErr.Line)
+			}
+		})
+	}
+}
+
+func TestParseFirewallRules(t *testing.T) {
+	text := `
+# web servers
+in accept tcp to any port 80,443 # http
+in accept tcp from 192.0.2.10 port 1024:65535 to any port 22
+
+in drop
+`
+	rules, err := ParseFirewallRules(text)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []FirewallPolicyRule{
+		{Action: FirewallActionAccept, Comment: "http", Direction: FirewallDirectionIn, DestinationPort: "80,443", Protocol: FirewallProtocolTCP},
+		{Action: FirewallActionAccept, Direction: FirewallDirectionIn, DestinationPort: "22", Protocol: FirewallProtocolTCP, SourceIP: "192.0.2.10", SourcePort: "1024:65535"},
+		{Action: FirewallActionDrop, Direction: FirewallDirectionIn},
+	}, rules)
+}
+
+func TestParseFirewallRules_invalid(t *testing.T) {
+	_, err := ParseFirewallRules("in accept tcp\n\nin accept tcp to any port 22-23\n")
+
+	assert.EqualError(t, err, `cloudsigma-sdk-go: firewall rule on line 3: invalid ports "22-23": "in accept tcp to any port 22-23"`)
+}
+
+func TestFormatFirewallRule(t *testing.T) {
+	text, err := FormatFirewallRule(FirewallPolicyRule{
+		Action:          FirewallActionAccept,
+		Comment:         "ssh",
+		Direction:       FirewallDirectionIn,
+		DestinationPort: "22,443",
+		Protocol:        FirewallProtocolTCP,
+		SourceIP:        "10.0.0.0/8",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "in accept tcp from 10.0.0.0/8 to any port 22,443 # ssh", text)
+
+	text, err = FormatFirewallRule(FirewallPolicyRule{Action: FirewallActionDrop, Direction: FirewallDirectionOut})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "out drop from any to any", text)
+}
+
+func TestFormatFirewallRule_invalid(t *testing.T) {
+	tests := []FirewallPolicyRule{
+		{Action: FirewallActionAccept},
+		{Direction: FirewallDirectionIn},
+		{Action: FirewallActionAccept, Direction: FirewallDirectionIn, Protocol: "icmp"},
+		{Action: FirewallActionAccept, Direction: FirewallDirectionIn, SourceIP: "example.com"},
+		{Action: FirewallActionAccept, Direction: FirewallDirectionIn, DestinationPort: "22"},
+		{Action: FirewallActionAccept, Direction: FirewallDirectionIn, Comment: "two\nlines"},
+		{Action: FirewallActionAccept, Direction: FirewallDirectionIn, Comment: " padded"},
+	}
+	for i, rule := range tests {
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			_, err := FormatFirewallRule(rule)
+
+			var syntaxErr *FirewallRuleSyntaxError
+			assert.ErrorAs(t, err, &syntaxErr)
+		})
+	}
+}
+
+func TestFormatFirewallRules_roundTrip(t *testing.T) {
+	text := "in accept tcp from 10.0.0.0/8 to any port 22,443 # ssh\n" +
+		"in accept udp from 2001:DB8::1 port 53 to any # resolver # primary\n" +
+		"out accept from any to 198.51.100.0/24\n" +
+		"in drop from any to any\n"
+
+	rules, err := ParseFirewallRules(text)
+	assert.NoError(t, err)
+
+	formatted, err := FormatFirewallRules(rules)
+
+	assert.NoError(t, err)
+	assert.Equal(t, text, formatted)
+}
+
+func TestFormatFirewallRules_invalid(t *testing.T) {
+	rules := []FirewallPolicyRule{
+		{Action: FirewallActionDrop, Direction: FirewallDirectionIn},
+		{Action: FirewallActionDrop, Direction: FirewallDirectionIn, SourcePort: "22"},
+	}
+
+	_, err := FormatFirewallRules(rules)
+
+	var syntaxErr *FirewallRuleSyntaxError
+	if assert.ErrorAs(t, err, &syntaxErr) {
+		assert.Equal(t, 2, syntaxErr.Line)
+	}
+}
+
+func TestFirewallRules_roundTripThroughService(t *testing.T) {
+	setup()
+	defer teardown()
+
+	text := "in accept tcp from 10.0.0.0/8 to any port 22,443 # ssh\n" +
+		"in drop from any to any\n"
+	rules, err := ParseFirewallRules(text)
+	assert.NoError(t, err)
+
+	mux.HandleFunc("/fwpolicies/long-uuid/", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		body, _ := io.ReadAll(r.Body)
+		var policy map[string]interface{}
+		assert.NoError(t, json.Unmarshal(body, &policy))
+		assert.Equal(t, []interface{}{
+			map[string]interface{}{"action": "accept", "comment": "ssh", "direction": "in", "dst_port": "22,443", "ip_proto": "tcp", "src_ip": "10.0.0.0/8"},
+			map[string]interface{}{"action": "drop", "direction": "in"},
+		}, policy["rules"])
+		_, _ = w.Write(body)
+	})
+
+	policy, _, err := client.FirewallPolicies.Update(ctx, "long-uuid", &FirewallPolicyUpdateRequest{FirewallPolicy: &FirewallPolicy{Rules: rules}})
+	assert.NoError(t, err)
+
+	formatted, err := FormatFirewallRules(policy.Rules)
+
+	assert.NoError(t, err)
+	assert.Equal(t, text, formatted)
+}
@@ -2,6 +2,7 @@ package cloudsigma
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 )
@@ -24,6 +25,27 @@ type IP struct {
 	ResourceURI string                 `json:"resource_uri,omitempty"`
 	Server      *ResourceLink          `json:"server,omitempty"`
 	UUID        string                 `json:"uuid,omitempty"`
+
+	// Extra holds API fields unknown to the SDK, see Server.Extra.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// MarshalJSON is a custom marshaller for IP. It adds the fields of Extra.
+func (i IP) MarshalJSON() ([]byte, error) {
+	type Alias IP
+	return marshalWithExtra(Alias(i), i.Extra)
+}
+
+// UnmarshalJSON is a custom unmarshaller for IP. It keeps the fields
+// unknown to the SDK in Extra.
+func (i *IP) UnmarshalJSON(data []byte) error {
+	type Alias IP
+	extra, err := unmarshalWithExtra(data, (*Alias)(i))
+	if err != nil {
+		return err
+	}
+	i.Extra = extra
+	return nil
 }
 
 type ipsRoot struct {
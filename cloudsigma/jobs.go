@@ -0,0 +1,184 @@
+package cloudsigma
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const jobsBasePath = "jobs"
+
+// ErrJobFailed is returned by JobsService.WaitForJob when the job fails.
+var ErrJobFailed = errors.New("cloudsigma-sdk-go: job failed")
+
+// JobsService handles communication with the jobs related methods of the
+// CloudSigma API. Jobs track long-running operations like drive cloning or
+// resizing.
+//
+// CloudSigma API docs: https://cloudsigma-docs.readthedocs.io/en/latest/jobs.html
+type JobsService service
+
+// JobState represents the state of a job.
+type JobState string
+
+// Job states.
+const (
+	JobStateStarted JobState = "started"
+	JobStateSuccess JobState = "success"
+	JobStateFailed  JobState = "failed"
+)
+
+// IsTerminal reports whether the job has finished, successfully or not.
+func (s JobState) IsTerminal() bool {
+	return s == JobStateSuccess || s == JobStateFailed
+}
+
+// Job represents a CloudSigma job.
+type Job struct {
+	Children     []string `json:"children,omitempty"`
+	Created      string   `json:"created,omitempty"`
+	Data         *JobData `json:"data,omitempty"`
+	LastModified string   `json:"last_modified,omitempty"`
+	Operation    string   `json:"operation,omitempty"`
+	Resources    []string `json:"resources,omitempty"`
+	ResourceURI  string   `json:"resource_uri,omitempty"`
+	State        JobState `json:"state,omitempty"`
+	UUID         string   `json:"uuid,omitempty"`
+}
+
+// JobData represents the progress of a job in percent.
+type JobData struct {
+	Progress int `json:"progress,omitempty"`
+}
+
+// Progress returns the progress of the job in percent.
+func (j *Job) Progress() int {
+	if j.State == JobStateSuccess {
+		return 100
+	}
+	if j.Data == nil {
+		return 0
+	}
+	return j.Data.Progress
+}
+
+// HasResource reports whether the job operates on the resource identified
+// by uuid.
+func (j *Job) HasResource(uuid string) bool {
+	for _, r := range j.Resources {
+		if r == uuid || strings.HasSuffix(strings.TrimSuffix(r, "/"), "/"+uuid) {
+			return true
+		}
+	}
+	return false
+}
+
+type jobsRoot struct {
+	Meta *Meta `json:"meta,omitempty"`
+	Jobs []Job `json:"objects"`
+}
+
+// List provides a list of jobs of the user.
+//
+// CloudSigma API docs: https://cloudsigma-docs.readthedocs.io/en/latest/jobs.html#listing
+func (s *JobsService) List(ctx context.Context, opts *ListOptions) ([]Job, *Response, error) {
+	path := fmt.Sprintf("%v/", jobsBasePath)
+	path, err := addOptions(path, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(jobsRoot)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+	if m := root.Meta; m != nil {
+		resp.Meta = m
+	}
+
+	return root.Jobs, resp, nil
+}
+
+// ListByResource provides a list of all jobs operating on the resource
+// identified by uuid, e.g. a drive.
+func (s *JobsService) ListByResource(ctx context.Context, uuid string) ([]Job, *Response, error) {
+	if uuid == "" {
+		return nil, nil, ErrEmptyArgument
+	}
+
+	jobs, resp, err := s.List(ctx, &ListOptions{Limit: 0})
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var filtered []Job
+	for _, job := range jobs {
+		if job.HasResource(uuid) {
+			filtered = append(filtered, job)
+		}
+	}
+
+	return filtered, resp, nil
+}
+
+// Get provides detailed information for a job identified by uuid.
+//
+// CloudSigma API docs: https://cloudsigma-docs.readthedocs.io/en/latest/jobs.html#job-details
+func (s *JobsService) Get(ctx context.Context, uuid string) (*Job, *Response, error) {
+	if uuid == "" {
+		return nil, nil, ErrEmptyArgument
+	}
+
+	path := fmt.Sprintf("%v/%v/", jobsBasePath, uuid)
+
+	req, err := s.client.NewRequest(http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	job := new(Job)
+	resp, err := s.client.Do(ctx, req, job)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return job, resp, nil
+}
+
+// WaitForJob polls a job identified by uuid until it finishes, and returns
+// the finished job. progress is optional and called with every polled job.
+// An error wrapping ErrJobFailed is returned if the job fails.
+func (s *JobsService) WaitForJob(ctx context.Context, uuid string, progress func(*Job)) (*Job, error) {
+	if uuid == "" {
+		return nil, ErrEmptyArgument
+	}
+
+	var job *Job
+	err := s.client.poll(ctx, func() (bool, error) {
+		j, _, err := s.Get(ctx, uuid)
+		if err != nil {
+			return false, err
+		}
+		job = j
+		if progress != nil {
+			progress(job)
+		}
+		return job.State.IsTerminal(), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if job.State == JobStateFailed {
+		return job, fmt.Errorf("%w: %v %v", ErrJobFailed, job.Operation, job.UUID)
+	}
+
+	return job, nil
+}
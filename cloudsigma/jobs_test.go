@@ -0,0 +1,127 @@
+package cloudsigma
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobs_List(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/jobs/", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		_, _ = fmt.Fprint(w, `{"objects":[{"operation":"drive_clone","state":"started","uuid":"long-uuid"}],"meta":{"total_count":1}}`)
+	})
+	expected := []Job{
+		{
+			Operation: "drive_clone",
+			State:     JobStateStarted,
+			UUID:      "long-uuid",
+		},
+	}
+
+	jobs, resp, err := client.Jobs.List(ctx, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, jobs)
+	assert.Equal(t, 1, resp.Meta.TotalCount)
+}
+
+func TestJobs_ListByResource(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/jobs/", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "0", r.URL.Query().Get("limit"))
+		_, _ = fmt.Fprint(w, `{"objects":[
+			{"resources":["/api/2.0/drives/drive-uuid/"],"uuid":"job-1"},
+			{"resources":["/api/2.0/drives/other-uuid/"],"uuid":"job-2"},
+			{"resources":["drive-uuid"],"uuid":"job-3"}
+		]}`)
+	})
+
+	jobs, _, err := client.Jobs.ListByResource(ctx, "drive-uuid")
+
+	assert.NoError(t, err)
+	assert.Len(t, jobs, 2)
+	assert.Equal(t, "job-1", jobs[0].UUID)
+	assert.Equal(t, "job-3", jobs[1].UUID)
+}
+
+func TestJobs_Get(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/jobs/long-uuid/", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		_, _ = fmt.Fprint(w, `{"data":{"progress":40},"state":"started","uuid":"long-uuid"}`)
+	})
+	expected := &Job{
+		Data:  &JobData{Progress: 40},
+		State: JobStateStarted,
+		UUID:  "long-uuid",
+	}
+
+	job, _, err := client.Jobs.Get(ctx, "long-uuid")
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, job)
+	assert.Equal(t, 40, job.Progress())
+}
+
+func TestJobs_Get_emptyUUID(t *testing.T) {
+	_, _, err := client.Jobs.Get(ctx, "")
+
+	assert.Error(t, err)
+	assert.Equal(t, ErrEmptyArgument.Error(), err.Error())
+}
+
+func TestJobs_WaitForJob(t *testing.T) {
+	setup()
+	defer teardown()
+
+	calls := 0
+	mux.HandleFunc("/jobs/long-uuid/", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			_, _ = fmt.Fprintf(w, `{"data":{"progress":%d},"state":"started","uuid":"long-uuid"}`, calls*30)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"state":"success","uuid":"long-uuid"}`)
+	})
+	var progress []int
+
+	job, err := client.Jobs.WaitForJob(ctx, "long-uuid", func(j *Job) {
+		progress = append(progress, j.Progress())
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, JobStateSuccess, job.State)
+	assert.Equal(t, []int{30, 60, 100}, progress)
+}
+
+func TestJobs_WaitForJob_failed(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/jobs/long-uuid/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"operation":"drive_resize","state":"failed","uuid":"long-uuid"}`)
+	})
+
+	job, err := client.Jobs.WaitForJob(ctx, "long-uuid", nil)
+
+	assert.True(t, errors.Is(err, ErrJobFailed))
+	assert.Equal(t, JobStateFailed, job.State)
+}
+
+func TestJobState_IsTerminal(t *testing.T) {
+	assert.False(t, JobStateStarted.IsTerminal())
+	assert.True(t, JobStateSuccess.IsTerminal())
+	assert.True(t, JobStateFailed.IsTerminal())
+	assert.False(t, JobState("queued").IsTerminal())
+}
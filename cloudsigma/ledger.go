@@ -0,0 +1,106 @@
+package cloudsigma
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const ledgerBasePath = "ledger"
+
+// LedgerService handles communication with the ledger related methods of
+// the CloudSigma API.
+//
+// CloudSigma API docs: https://cloudsigma-docs.readthedocs.io/en/latest/billing.html#ledger
+type LedgerService service
+
+// LedgerEntry represents a CloudSigma ledger entry, i.e. a single change of
+// the account balance. Charges have a negative amount.
+type LedgerEntry struct {
+	Amount       Decimal       `json:"amount"`
+	BillingCycle int           `json:"billing_cycle,omitempty"`
+	End          string        `json:"end,omitempty"`
+	ID           string        `json:"id,omitempty"`
+	Interval     int           `json:"interval,omitempty"`
+	Reason       string        `json:"reason,omitempty"`
+	ResourceURI  string        `json:"resource_uri,omitempty"`
+	Start        string        `json:"start,omitempty"`
+	Time         string        `json:"time,omitempty"`
+	User         *ResourceLink `json:"user,omitempty"`
+}
+
+// LedgerListOptions specifies the optional parameters to the
+// LedgerService.List.
+type LedgerListOptions struct {
+	// Since filters entries created at or after the given time.
+	Since time.Time `url:"time__gte,omitempty"`
+	// Until filters entries created before the given time.
+	Until time.Time `url:"time__lt,omitempty"`
+	// Reason filters entries whose reason contains the given text.
+	Reason string `url:"reason__icontains,omitempty"`
+
+	ListOptions
+}
+
+type ledgerRoot struct {
+	Meta          *Meta         `json:"meta,omitempty"`
+	LedgerEntries []LedgerEntry `json:"objects"`
+}
+
+// List provides a list of ledger entries of the user, optionally filtered
+// by time range and paginated.
+//
+// CloudSigma API docs: https://cloudsigma-docs.readthedocs.io/en/latest/billing.html#ledger
+func (s *LedgerService) List(ctx context.Context, opts *LedgerListOptions) ([]LedgerEntry, *Response, error) {
+	path := fmt.Sprintf("%v/", ledgerBasePath)
+	path, err := addOptions(path, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(ledgerRoot)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+	if m := root.Meta; m != nil {
+		resp.Meta = m
+	}
+
+	return root.LedgerEntries, resp, nil
+}
+
+// Get provides a detailed information for a ledger entry identified by id.
+//
+// CloudSigma API docs: https://cloudsigma-docs.readthedocs.io/en/latest/billing.html#ledger
+func (s *LedgerService) Get(ctx context.Context, id string) (*LedgerEntry, *Response, error) {
+	if id == "" {
+		return nil, nil, ErrEmptyArgument
+	}
+
+	path := fmt.Sprintf("%v/%v/", ledgerBasePath, id)
+
+	req, err := s.client.NewRequest(http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	entry := new(LedgerEntry)
+	resp, err := s.client.Do(ctx, req, entry)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return entry, resp, nil
+}
+
+// ParsedTime parses the time of the ledger entry.
+func (e LedgerEntry) ParsedTime() (time.Time, error) {
+	return parseTimestamp(e.Time)
+}
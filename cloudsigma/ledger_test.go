@@ -0,0 +1,73 @@
+package cloudsigma
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLedger_List(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/ledger/", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "2024-06-01T00:00:00Z", r.URL.Query().Get("time__gte"))
+		assert.Equal(t, "2024-07-01T00:00:00Z", r.URL.Query().Get("time__lt"))
+		assert.Equal(t, "50", r.URL.Query().Get("limit"))
+		assert.Equal(t, "100", r.URL.Query().Get("offset"))
+		_, _ = fmt.Fprint(w, `{"objects":[{"amount":"-0.01032","id":"1500","reason":"Burst: cpu","time":"2024-06-02T10:00:00+00:00"}],"meta":{"total_count":101}}`)
+	})
+	expected := []LedgerEntry{
+		{
+			Amount: MustParseDecimal("-0.01032"),
+			ID:     "1500",
+			Reason: "Burst: cpu",
+			Time:   "2024-06-02T10:00:00+00:00",
+		},
+	}
+	opts := &LedgerListOptions{
+		Since:       time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+		Until:       time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC),
+		ListOptions: ListOptions{Limit: 50, Offset: 100},
+	}
+
+	entries, resp, err := client.Ledger.List(ctx, opts)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, entries)
+	assert.Equal(t, 101, resp.Meta.TotalCount)
+	parsed, err := entries[0].ParsedTime()
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 6, 2, 10, 0, 0, 0, time.UTC), parsed.UTC())
+}
+
+func TestLedger_Get(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/ledger/1500/", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		_, _ = fmt.Fprint(w, `{"amount":"100.00","id":"1500","reason":"Top-up"}`)
+	})
+	expected := &LedgerEntry{
+		Amount: MustParseDecimal("100.00"),
+		ID:     "1500",
+		Reason: "Top-up",
+	}
+
+	entry, _, err := client.Ledger.Get(ctx, "1500")
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, entry)
+}
+
+func TestLedger_Get_emptyID(t *testing.T) {
+	_, _, err := client.Ledger.Get(ctx, "")
+
+	assert.Error(t, err)
+	assert.Equal(t, ErrEmptyArgument.Error(), err.Error())
+}
@@ -4,6 +4,10 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
 )
 
 const libdrivesBasePath = "libdrives"
@@ -30,6 +34,7 @@ type LibraryDrive struct {
 	Favourite         bool                   `json:"favourite,omitempty"`
 	ImageType         string                 `json:"image_type,omitempty"`
 	InstallNotes      string                 `json:"install_notes,omitempty"`
+	Jobs              []ResourceLink         `json:"jobs,omitempty"`
 	Licenses          []DriveLicense         `json:"licenses,omitempty"`
 	Media             string                 `json:"media,omitempty"`
 	Meta              map[string]interface{} `json:"meta,omitempty"`
@@ -76,6 +81,27 @@ type LibraryDriveListOptions struct {
 	ListOptions
 }
 
+// LibraryDriveSelector specifies the criteria used to select library drives
+// by SelectLibraryDrives and LibraryDrivesService.FindLatest.
+type LibraryDriveSelector struct {
+	// Distribution selects library drives of an operating system distribution
+	// (case insensitive), e.g. "Ubuntu".
+	Distribution string
+	// Version selects library drives whose version starts with the given
+	// version components, e.g. "24.04" matches "24.04" and "24.04.1".
+	Version string
+	// Arch selects library drives of an operating system bit architecture,
+	// e.g. "64".
+	Arch string
+	// ImageTypes lists accepted image types in the order of preference. All
+	// image types are accepted if empty.
+	ImageTypes []string
+	// IncludeDeprecated allows selecting deprecated library drives.
+	IncludeDeprecated bool
+	// IncludePaid allows selecting paid library drives.
+	IncludePaid bool
+}
+
 type libraryDrivesRoot struct {
 	LibraryDrives []LibraryDrive `json:"objects"`
 	Meta          *Meta          `json:"meta,omitempty"`
@@ -137,7 +163,8 @@ func (s *LibraryDrivesService) Get(ctx context.Context, uuid string) (*LibraryDr
 }
 
 // Clone duplicates a drive. LibraryDriveCloneRequest is optional. Size of the
-// cloned drive can only be bigger or the same.
+// cloned drive can only be bigger or the same. The cloned drive lists the
+// clone job in Jobs, which can be awaited with JobsService.WaitForJob.
 //
 // CloudSigma API docs: https://cloudsigma-docs.readthedocs.io/en/latest/libdrives.html#cloning-library-drive
 func (s *LibraryDrivesService) Clone(ctx context.Context, uuid string, cloneRequest *LibraryDriveCloneRequest) (*LibraryDrive, *Response, error) {
@@ -152,6 +179,10 @@ func (s *LibraryDrivesService) Clone(ctx context.Context, uuid string, cloneRequ
 		return nil, nil, err
 	}
 	if cloneRequest != nil {
+		// jobs are read-only
+		if cloneRequest.LibraryDrive != nil {
+			cloneRequest.Jobs = nil
+		}
 		req, err = s.client.NewRequest(http.MethodPost, path, cloneRequest)
 		if err != nil {
 			return nil, nil, err
@@ -166,3 +197,158 @@ func (s *LibraryDrivesService) Clone(ctx context.Context, uuid string, cloneRequ
 
 	return &root.LibraryDrives[0], resp, nil
 }
+
+// FindLatest provides the best library drive matching the selector. See
+// SelectLibraryDrives for the ordering of matching library drives. If no
+// library drive matches, ErrResourceNotFound is returned.
+//
+// CloudSigma API docs: https://cloudsigma-docs.readthedocs.io/en/latest/libdrives.html#listing
+func (s *LibraryDrivesService) FindLatest(ctx context.Context, selector *LibraryDriveSelector) (*LibraryDrive, *Response, error) {
+	if selector == nil {
+		return nil, nil, ErrEmptyArgument
+	}
+
+	opts := &LibraryDriveListOptions{ImageTypes: selector.ImageTypes}
+	if selector.Distribution != "" {
+		opts.Distributions = []string{selector.Distribution}
+	}
+	if arch, err := strconv.Atoi(selector.Arch); err == nil {
+		opts.Arch = arch
+	}
+
+	libdrives, resp, err := s.List(ctx, opts)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	candidates := SelectLibraryDrives(libdrives, selector)
+	if len(candidates) == 0 {
+		return nil, resp, ErrResourceNotFound
+	}
+
+	return &candidates[0], resp, nil
+}
+
+// CloneLatest clones the best library drive matching the selector and waits
+// until the cloned drive becomes available (unmounted). LibraryDriveCloneRequest
+// is optional. ErrDriveUnavailable is returned if the clone fails. Use a
+// context with a deadline to limit the waiting time.
+func (s *LibraryDrivesService) CloneLatest(ctx context.Context, selector *LibraryDriveSelector, cloneRequest *LibraryDriveCloneRequest) (*Drive, error) {
+	libdrive, _, err := s.FindLatest(ctx, selector)
+	if err != nil {
+		return nil, err
+	}
+
+	clone, _, err := s.Clone(ctx, libdrive.UUID, cloneRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	drives := (*DrivesService)(s)
+	return drives.WaitForStatus(ctx, clone.UUID, DriveStatusUnmounted)
+}
+
+// SelectLibraryDrives returns library drives matching the selector, ordered
+// from the best match. Library drives are ordered by the preference of their
+// image type, then by their version (newest first, comparing numeric version
+// components), and then by their creation time (newest first).
+func SelectLibraryDrives(libdrives []LibraryDrive, selector *LibraryDriveSelector) []LibraryDrive {
+	if selector == nil {
+		selector = &LibraryDriveSelector{}
+	}
+
+	imageTypeRanks := make(map[string]int, len(selector.ImageTypes))
+	for i, imageType := range selector.ImageTypes {
+		if _, ok := imageTypeRanks[imageType]; !ok {
+			imageTypeRanks[imageType] = i
+		}
+	}
+	wantedVersion := versionComponents(selector.Version)
+
+	var candidates []LibraryDrive
+	for _, l := range libdrives {
+		if l.Deprecated && !selector.IncludeDeprecated {
+			continue
+		}
+		if l.Paid && !selector.IncludePaid {
+			continue
+		}
+		if selector.Distribution != "" && !strings.EqualFold(l.Distribution, selector.Distribution) {
+			continue
+		}
+		if selector.Arch != "" && l.Arch != selector.Arch {
+			continue
+		}
+		if _, ok := imageTypeRanks[l.ImageType]; len(imageTypeRanks) > 0 && !ok {
+			continue
+		}
+		if !hasVersionPrefix(versionComponents(l.Version), wantedVersion) {
+			continue
+		}
+		candidates = append(candidates, l)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if imageTypeRanks[a.ImageType] != imageTypeRanks[b.ImageType] {
+			return imageTypeRanks[a.ImageType] < imageTypeRanks[b.ImageType]
+		}
+		if c := compareVersions(a.Version, b.Version); c != 0 {
+			return c > 0
+		}
+		createdA, _ := parseTimestamp(a.CreatedAt)
+		createdB, _ := parseTimestamp(b.CreatedAt)
+		return createdA.After(createdB)
+	})
+
+	return candidates
+}
+
+// compareVersions compares the numeric components of versions a and b, and
+// returns -1 if a is older than b, 1 if a is newer than b, or 0 otherwise.
+// Missing components are older, so "24.04.1" is newer than "24.04".
+func compareVersions(a, b string) int {
+	va, vb := versionComponents(a), versionComponents(b)
+	for i := 0; i < len(va) && i < len(vb); i++ {
+		if va[i] != vb[i] {
+			if va[i] < vb[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case len(va) < len(vb):
+		return -1
+	case len(va) > len(vb):
+		return 1
+	}
+	return 0
+}
+
+// versionComponents returns all numeric components of version, e.g.
+// "24.04.1 LTS" has components 24, 4 and 1.
+func versionComponents(version string) []int {
+	fields := strings.FieldsFunc(version, func(r rune) bool { return !unicode.IsDigit(r) })
+	components := make([]int, 0, len(fields))
+	for _, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil {
+			continue
+		}
+		components = append(components, n)
+	}
+	return components
+}
+
+func hasVersionPrefix(version, prefix []int) bool {
+	if len(prefix) > len(version) {
+		return false
+	}
+	for i := range prefix {
+		if version[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
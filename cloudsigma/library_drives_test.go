@@ -131,3 +131,140 @@ func TestLibraryDrives_Clone_invalidUUID(t *testing.T) {
 
 	assert.Error(t, err)
 }
+
+func TestLibraryDrives_FindLatest(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/libdrives/", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "ubuntu", r.URL.Query().Get("distribution"))
+		assert.Equal(t, "64", r.URL.Query().Get("arch"))
+		_, _ = fmt.Fprint(w, `{"objects":[
+			{"arch":"64","distribution":"Ubuntu","version":"24.04","image_type":"install","uuid":"install-uuid"},
+			{"arch":"64","distribution":"Ubuntu","version":"24.04","image_type":"preinst","created_at":"2024-05-01T10:00:00+00:00","uuid":"old-uuid"},
+			{"arch":"64","distribution":"Ubuntu","version":"24.04","image_type":"preinst","created_at":"2024-09-01T10:00:00+00:00","uuid":"new-uuid"},
+			{"arch":"64","distribution":"Ubuntu","version":"24.10","image_type":"preinst","uuid":"other-version-uuid"},
+			{"arch":"64","distribution":"Ubuntu","version":"24.04.1","image_type":"preinst","deprecated":true,"uuid":"deprecated-uuid"}
+		]}`)
+	})
+	selector := &LibraryDriveSelector{
+		Distribution: "ubuntu",
+		Version:      "24.04",
+		Arch:         "64",
+		ImageTypes:   []string{"preinst", "install"},
+	}
+
+	libraryDrive, _, err := client.LibraryDrives.FindLatest(ctx, selector)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "new-uuid", libraryDrive.UUID)
+}
+
+func TestLibraryDrives_FindLatest_notFound(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/libdrives/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"objects":[{"distribution":"Debian","version":"12","uuid":"long-uuid"}]}`)
+	})
+
+	_, _, err := client.LibraryDrives.FindLatest(ctx, &LibraryDriveSelector{Distribution: "Ubuntu"})
+
+	assert.ErrorIs(t, err, ErrResourceNotFound)
+}
+
+func TestLibraryDrives_FindLatest_emptySelector(t *testing.T) {
+	_, _, err := client.LibraryDrives.FindLatest(ctx, nil)
+
+	assert.Error(t, err)
+	assert.Equal(t, ErrEmptyArgument.Error(), err.Error())
+}
+
+func TestLibraryDrives_CloneLatest(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/libdrives/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"objects":[{"distribution":"Debian","version":"12","uuid":"library-uuid"}]}`)
+	})
+	mux.HandleFunc("/libdrives/library-uuid/action/", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "clone", r.URL.Query().Get("do"))
+		_, _ = fmt.Fprint(w, `{"objects":[{"status":"cloning_dst","uuid":"clone-uuid"}]}`)
+	})
+	polls := 0
+	mux.HandleFunc("/drives/clone-uuid/", func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		if polls < 3 {
+			_, _ = fmt.Fprint(w, `{"status":"cloning_dst","uuid":"clone-uuid"}`)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"status":"unmounted","uuid":"clone-uuid"}`)
+	})
+	expected := &Drive{Status: "unmounted", UUID: "clone-uuid"}
+
+	drive, err := client.LibraryDrives.CloneLatest(ctx, &LibraryDriveSelector{Distribution: "Debian"}, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, drive)
+	assert.Equal(t, 3, polls)
+}
+
+func TestLibraryDrives_CloneLatest_unavailable(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/libdrives/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"objects":[{"distribution":"Debian","version":"12","uuid":"library-uuid"}]}`)
+	})
+	mux.HandleFunc("/libdrives/library-uuid/action/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"objects":[{"status":"cloning_dst","uuid":"clone-uuid"}]}`)
+	})
+	mux.HandleFunc("/drives/clone-uuid/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"status":"unavailable","uuid":"clone-uuid"}`)
+	})
+
+	_, err := client.LibraryDrives.CloneLatest(ctx, &LibraryDriveSelector{Distribution: "Debian"}, nil)
+
+	assert.ErrorIs(t, err, ErrDriveUnavailable)
+}
+
+func TestLibraryDrives_SelectLibraryDrives(t *testing.T) {
+	libdrives := []LibraryDrive{
+		{UUID: "paid", Version: "9", Paid: true},
+		{UUID: "v8", Version: "8"},
+		{UUID: "v10", Version: "10"},
+		{UUID: "v9.1", Version: "9.1"},
+		{UUID: "v9", Version: "9"},
+	}
+
+	selected := SelectLibraryDrives(libdrives, nil)
+	var uuids []string
+	for _, l := range selected {
+		uuids = append(uuids, l.UUID)
+	}
+
+	assert.Equal(t, []string{"v10", "v9.1", "v9", "v8"}, uuids)
+	assert.Len(t, SelectLibraryDrives(libdrives, &LibraryDriveSelector{IncludePaid: true}), 5)
+	assert.Len(t, SelectLibraryDrives(libdrives, &LibraryDriveSelector{Version: "9"}), 2)
+}
+
+func TestLibraryDrives_compareVersions(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"24.04", "22.04", 1},
+		{"22.04", "24.04", -1},
+		{"24.04.1", "24.04", 1},
+		{"24.10", "24.04", 1},
+		{"10", "9", 1},
+		{"v1.2.3", "1.2.3", 0},
+		{"24.04 LTS", "24.04", 0},
+		{"", "", 0},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, compareVersions(tt.a, tt.b), "%q <=> %q", tt.a, tt.b)
+	}
+}
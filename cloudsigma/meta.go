@@ -0,0 +1,249 @@
+package cloudsigma
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ResourceMeta provides typed access to the meta field of CloudSigma
+// resources such as Server, Drive, IP, VLAN, Tag, ACL, Snapshot, Keypair and
+// FirewallPolicy, e.g. ResourceMeta(server.Meta).GetInt("port", 22).
+//
+// CloudSigma stores meta values as strings, but values set by other clients
+// may have been decoded from JSON numbers or booleans, so both are accepted.
+type ResourceMeta map[string]interface{}
+
+// GetString returns the value of key as string, or def if it is missing.
+// Numbers and booleans are formatted.
+func (m ResourceMeta) GetString(key, def string) string {
+	switch v := m[key].(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return def
+}
+
+// GetInt returns the value of key as int, or def if it is missing or not an
+// integer.
+func (m ResourceMeta) GetInt(key string, def int) int {
+	switch v := m[key].(type) {
+	case string:
+		if i, err := strconv.Atoi(strings.TrimSpace(v)); err == nil {
+			return i
+		}
+	case float64:
+		if v == float64(int(v)) {
+			return int(v)
+		}
+	}
+	return def
+}
+
+// GetBool returns the value of key as bool, or def if it is missing or not
+// a boolean as accepted by strconv.ParseBool.
+func (m ResourceMeta) GetBool(key string, def bool) bool {
+	switch v := m[key].(type) {
+	case string:
+		if b, err := strconv.ParseBool(strings.TrimSpace(v)); err == nil {
+			return b
+		}
+	case bool:
+		return v
+	}
+	return def
+}
+
+// GetTime returns the value of key as time, or def if it is missing or not
+// a timestamp. Timestamps without time zone are treated as UTC.
+func (m ResourceMeta) GetTime(key string, def time.Time) time.Time {
+	if v, ok := m[key].(string); ok {
+		if t, err := parseTimestamp(strings.TrimSpace(v)); err == nil {
+			return t
+		}
+	}
+	return def
+}
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// EncodeMeta returns the fields of the struct pointed to by v as meta with
+// string values. Fields are named by their "meta" struct tag, e.g.
+// `meta:"backup_schedule,omitempty"`; fields without tag or tagged "-" are
+// skipped, as are nil pointers and empty fields with the "omitempty" option.
+//
+// Strings, booleans and numbers are formatted with strconv, types
+// implementing encoding.TextMarshaler such as time.Time with MarshalText, and
+// all other types as JSON. DecodeMeta accepts every timestamp format of the
+// CloudSigma API for time.Time fields.
+func EncodeMeta(v interface{}) (map[string]interface{}, error) {
+	rv, err := metaStruct(v)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := make(map[string]interface{})
+	for i := 0; i < rv.NumField(); i++ {
+		key, omitEmpty, ok := metaTag(rv.Type().Field(i))
+		if !ok {
+			continue
+		}
+		field := rv.Field(i)
+		if omitEmpty && field.IsZero() || field.Kind() == reflect.Ptr && field.IsNil() {
+			continue
+		}
+		value, err := encodeMetaValue(field)
+		if err != nil {
+			return nil, fmt.Errorf("cloudsigma-sdk-go: cannot encode meta %q: %w", key, err)
+		}
+		meta[key] = value
+	}
+	return meta, nil
+}
+
+// DecodeMeta sets the fields of the struct pointed to by v from meta, using
+// the "meta" struct tags as EncodeMeta does. Missing keys leave the fields
+// unchanged.
+func DecodeMeta(meta map[string]interface{}, v interface{}) error {
+	rv, err := metaStruct(v)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < rv.NumField(); i++ {
+		key, _, ok := metaTag(rv.Type().Field(i))
+		if !ok {
+			continue
+		}
+		value, ok := meta[key]
+		if !ok || value == nil {
+			continue
+		}
+		s := ResourceMeta(meta).GetString(key, "")
+		if _, ok := value.(string); !ok && s == "" {
+			// nested objects and arrays are decoded as JSON
+			b, err := json.Marshal(value)
+			if err != nil {
+				return fmt.Errorf("cloudsigma-sdk-go: cannot decode meta %q: %w", key, err)
+			}
+			s = string(b)
+		}
+		if err := decodeMetaValue(rv.Field(i), s); err != nil {
+			return fmt.Errorf("cloudsigma-sdk-go: cannot decode meta %q: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// metaStruct returns the struct pointed to by v.
+func metaStruct(v interface{}) (reflect.Value, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("cloudsigma-sdk-go: meta requires a non-nil struct pointer, got %T", v)
+	}
+	return rv.Elem(), nil
+}
+
+// metaTag returns the key and options of an exported struct field with a
+// "meta" tag.
+func metaTag(field reflect.StructField) (key string, omitEmpty, ok bool) {
+	tag, ok := field.Tag.Lookup("meta")
+	if !ok || tag == "-" || !field.IsExported() {
+		return "", false, false
+	}
+	key, options, _ := strings.Cut(tag, ",")
+	if key == "" {
+		key = field.Name
+	}
+	return key, options == "omitempty", true
+}
+
+func encodeMetaValue(v reflect.Value) (string, error) {
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if v.Type().Implements(textMarshalerType) {
+		b, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(b), err
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, v.Type().Bits()), nil
+	}
+
+	b, err := json.Marshal(v.Interface())
+	return string(b), err
+}
+
+func decodeMetaValue(v reflect.Value, s string) error {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	if v.Type() == timeType {
+		t, err := parseTimestamp(s)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	}
+	if v.Addr().Type().Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return json.Unmarshal([]byte(s), v.Addr().Interface())
+	}
+	return nil
+}
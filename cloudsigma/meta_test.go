@@ -0,0 +1,161 @@
+package cloudsigma
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testServerMeta struct {
+	Role     string            `meta:"role"`
+	Port     int               `meta:"port,omitempty"`
+	Backup   bool              `meta:"backup"`
+	Ratio    float64           `meta:"ratio,omitempty"`
+	Deployed time.Time         `meta:"deployed,omitempty"`
+	Labels   map[string]string `meta:"labels,omitempty"`
+	Untagged string
+	Skipped  string `meta:"-"`
+}
+
+type testPointerMeta struct {
+	Deployed *time.Time `meta:"deployed"`
+	Port     *int       `meta:"port"`
+	Backup   *bool      `meta:"backup"`
+}
+
+func TestResourceMeta_Get(t *testing.T) {
+	meta := ResourceMeta{
+		"name":     "web",
+		"port":     "8080",
+		"workers":  float64(4),
+		"ratio":    float64(1.5),
+		"backup":   "true",
+		"debug":    false,
+		"deployed": "2024-06-29 12:00:00",
+		"invalid":  "n/a",
+	}
+
+	assert.Equal(t, "web", meta.GetString("name", ""))
+	assert.Equal(t, "4", meta.GetString("workers", ""))
+	assert.Equal(t, "false", meta.GetString("debug", ""))
+	assert.Equal(t, "default", meta.GetString("missing", "default"))
+	assert.Equal(t, 8080, meta.GetInt("port", 0))
+	assert.Equal(t, 4, meta.GetInt("workers", 0))
+	assert.Equal(t, 7, meta.GetInt("ratio", 7))
+	assert.Equal(t, 7, meta.GetInt("invalid", 7))
+	assert.True(t, meta.GetBool("backup", false))
+	assert.False(t, meta.GetBool("debug", true))
+	assert.True(t, meta.GetBool("invalid", true))
+	assert.Equal(t, time.Date(2024, 6, 29, 12, 0, 0, 0, time.UTC), meta.GetTime("deployed", time.Time{}))
+	assert.Equal(t, time.Unix(0, 0), meta.GetTime("invalid", time.Unix(0, 0)))
+	assert.Equal(t, 22, ResourceMeta(nil).GetInt("port", 22))
+}
+
+func TestEncodeMeta(t *testing.T) {
+	meta, err := EncodeMeta(&testServerMeta{
+		Role:     "web",
+		Backup:   false,
+		Deployed: time.Date(2024, 6, 29, 12, 0, 0, 0, time.UTC),
+		Labels:   map[string]string{"team": "ops"},
+		Untagged: "untagged",
+		Skipped:  "skipped",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"role":     "web",
+		"backup":   "false",
+		"deployed": "2024-06-29T12:00:00Z",
+		"labels":   `{"team":"ops"}`,
+	}, meta)
+}
+
+func TestEncodeMeta_pointers(t *testing.T) {
+	deployed := time.Date(2024, 6, 29, 12, 0, 0, 0, time.UTC)
+	port := 8080
+
+	meta, err := EncodeMeta(&testPointerMeta{Deployed: &deployed, Port: &port})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"deployed": "2024-06-29T12:00:00Z", "port": "8080"}, meta)
+}
+
+func TestEncodeMeta_invalid(t *testing.T) {
+	_, err := EncodeMeta(testServerMeta{})
+
+	assert.EqualError(t, err, "cloudsigma-sdk-go: meta requires a non-nil struct pointer, got cloudsigma.testServerMeta")
+}
+
+func TestDecodeMeta(t *testing.T) {
+	v := testServerMeta{Role: "unchanged"}
+
+	err := DecodeMeta(map[string]interface{}{
+		"port":     "8080",
+		"backup":   true,
+		"ratio":    float64(0.5),
+		"deployed": "2024-06-29T12:00:00+02:00",
+		"labels":   map[string]interface{}{"team": "ops"},
+		"Untagged": "untagged",
+		"Skipped":  "skipped",
+	}, &v)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "unchanged", v.Role)
+	assert.Equal(t, 8080, v.Port)
+	assert.True(t, v.Backup)
+	assert.Equal(t, 0.5, v.Ratio)
+	assert.True(t, time.Date(2024, 6, 29, 10, 0, 0, 0, time.UTC).Equal(v.Deployed))
+	assert.Equal(t, map[string]string{"team": "ops"}, v.Labels)
+	assert.Empty(t, v.Untagged)
+	assert.Empty(t, v.Skipped)
+}
+
+func TestDecodeMeta_pointers(t *testing.T) {
+	var v testPointerMeta
+
+	err := DecodeMeta(map[string]interface{}{"deployed": "2024-06-29 12:00:00", "port": "8080"}, &v)
+
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 6, 29, 12, 0, 0, 0, time.UTC), *v.Deployed)
+	assert.Equal(t, 8080, *v.Port)
+	assert.Nil(t, v.Backup)
+}
+
+func TestDecodeMeta_invalid(t *testing.T) {
+	var v testServerMeta
+
+	err := DecodeMeta(map[string]interface{}{"port": "http"}, &v)
+
+	assert.ErrorContains(t, err, `cloudsigma-sdk-go: cannot decode meta "port"`)
+}
+
+func TestEncodeMeta_roundTrip(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/servers/long-uuid/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			_, _ = fmt.Fprint(w, `{"name":"web","uuid":"long-uuid","meta":{"role":"db","port":"5432","backup":"true"}}`)
+			return
+		}
+		v := make(map[string]interface{})
+		_ = json.NewDecoder(r.Body).Decode(&v)
+		assert.Equal(t, map[string]interface{}{"role": "db", "port": "5433", "backup": "true"}, v["meta"])
+		_, _ = fmt.Fprint(w, `{"name":"web","uuid":"long-uuid"}`)
+	})
+	server, _, err := client.Servers.Get(ctx, "long-uuid")
+	assert.NoError(t, err)
+	var meta testServerMeta
+	assert.NoError(t, DecodeMeta(server.Meta, &meta))
+	meta.Port++
+
+	server.Meta, err = EncodeMeta(&meta)
+	assert.NoError(t, err)
+	_, _, err = client.Servers.Update(ctx, server.UUID, &ServerUpdateRequest{Server: server})
+
+	assert.NoError(t, err)
+}
@@ -0,0 +1,147 @@
+package cloudsigma
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const pricingBasePath = "pricing"
+
+// PricingService handles communication with the pricing related methods of
+// the CloudSigma API.
+//
+// CloudSigma API docs: https://cloudsigma-docs.readthedocs.io/en/latest/pricing.html
+type PricingService service
+
+// Price represents a CloudSigma price of a resource at a price level in a
+// currency. Level 0 is the burst price, and higher levels are subscription
+// prices.
+//
+// Price is the price of one Unit, e.g. "GHz/30 days". Multiplier is the
+// number of base units (MHz, bytes, items) times seconds in one Unit, so the
+// price of a base unit per second is Price / Multiplier.
+type Price struct {
+	Currency   string  `json:"currency,omitempty"`
+	ID         string  `json:"id,omitempty"`
+	Level      int     `json:"level"`
+	Multiplier int64   `json:"multiplier,omitempty"`
+	Price      Decimal `json:"price"`
+	Resource   string  `json:"resource,omitempty"`
+	Unit       string  `json:"unit,omitempty"`
+}
+
+// Pricing represents the CloudSigma price list together with the current
+// and the next subscription price levels of the user per resource.
+type Pricing struct {
+	Current map[string]int
+	Next    map[string]int
+	Prices  []Price
+}
+
+type pricingMeta struct {
+	Meta
+	Current map[string]int `json:"current,omitempty"`
+	Next    map[string]int `json:"next,omitempty"`
+}
+
+type pricingRoot struct {
+	Meta   *pricingMeta `json:"meta,omitempty"`
+	Prices []Price      `json:"objects"`
+}
+
+// Get provides the whole price list with the current and next price levels
+// of the user.
+//
+// CloudSigma API docs: https://cloudsigma-docs.readthedocs.io/en/latest/pricing.html
+func (s *PricingService) Get(ctx context.Context) (*Pricing, *Response, error) {
+	path := fmt.Sprintf("%v/", pricingBasePath)
+	path, err := addOptions(path, &ListOptions{Limit: 0})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(pricingRoot)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	pricing := &Pricing{Prices: root.Prices}
+	if m := root.Meta; m != nil {
+		resp.Meta = &m.Meta
+		pricing.Current = m.Current
+		pricing.Next = m.Next
+	}
+
+	return pricing, resp, nil
+}
+
+// Price returns the price of the resource at the given level in the given
+// currency. Currencies are compared case-insensitively.
+func (p *Pricing) Price(resource, currency string, level int) (*Price, bool) {
+	for i := range p.Prices {
+		price := &p.Prices[i]
+		if price.Resource == resource && price.Level == level && strings.EqualFold(price.Currency, currency) {
+			return price, true
+		}
+	}
+	return nil, false
+}
+
+// BurstPrice returns the burst price of the resource in the given currency.
+func (p *Pricing) BurstPrice(resource, currency string) (*Price, bool) {
+	return p.Price(resource, currency, 0)
+}
+
+// CurrentPrice returns the subscription price of the resource at the current
+// price level of the user. If the current level is unknown, the lowest
+// subscription level is used, and resources without subscription prices
+// fall back to the burst price.
+func (p *Pricing) CurrentPrice(resource, currency string) (*Price, bool) {
+	if level, ok := p.Current[resource]; ok {
+		if price, ok := p.Price(resource, currency, level); ok {
+			return price, true
+		}
+	}
+
+	var lowest *Price
+	for i := range p.Prices {
+		price := &p.Prices[i]
+		if price.Resource != resource || price.Level == 0 || !strings.EqualFold(price.Currency, currency) {
+			continue
+		}
+		if lowest == nil || price.Level < lowest.Level {
+			lowest = price
+		}
+	}
+	if lowest != nil {
+		return lowest, true
+	}
+	return p.BurstPrice(resource, currency)
+}
+
+// NextPrice returns the subscription price of the resource at the next
+// price level of the user.
+func (p *Pricing) NextPrice(resource, currency string) (*Price, bool) {
+	level, ok := p.Next[resource]
+	if !ok {
+		return nil, false
+	}
+	return p.Price(resource, currency, level)
+}
+
+// Cost returns the cost of quantity base units of the resource for the
+// given number of seconds, rounded to 10 fractional digits.
+func (p *Price) Cost(quantity int64, seconds int64) Decimal {
+	if p.Multiplier == 0 {
+		return Decimal{}
+	}
+	return p.Price.Mul(NewDecimal(quantity, 0)).Mul(NewDecimal(seconds, 0)).Div(NewDecimal(p.Multiplier, 0), 10)
+}
@@ -0,0 +1,98 @@
+package cloudsigma
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const pricingJSON = `{
+	"meta": {"current": {"cpu": 1, "mem": 1, "dssd": 2}, "next": {"cpu": 2}, "total_count": 13},
+	"objects": [
+		{"currency": "USD", "level": 0, "multiplier": 2592000000, "price": "20", "resource": "cpu", "unit": "GHz/30 days"},
+		{"currency": "USD", "level": 1, "multiplier": 2592000000, "price": "10", "resource": "cpu", "unit": "GHz/30 days"},
+		{"currency": "USD", "level": 2, "multiplier": 2592000000, "price": "9", "resource": "cpu", "unit": "GHz/30 days"},
+		{"currency": "CHF", "level": 1, "multiplier": 2592000000, "price": "11", "resource": "cpu", "unit": "GHz/30 days"},
+		{"currency": "USD", "level": 0, "multiplier": 2783138807808000, "price": "15", "resource": "mem", "unit": "GB/30 days"},
+		{"currency": "USD", "level": 1, "multiplier": 2783138807808000, "price": "7.5", "resource": "mem", "unit": "GB/30 days"},
+		{"currency": "USD", "level": 0, "multiplier": 2783138807808000, "price": "0.2", "resource": "dssd", "unit": "GB/30 days"},
+		{"currency": "USD", "level": 2, "multiplier": 2783138807808000, "price": "0.1", "resource": "dssd", "unit": "GB/30 days"},
+		{"currency": "USD", "level": 0, "multiplier": 2783138807808000, "price": "0.05", "resource": "msd", "unit": "GB/30 days"},
+		{"currency": "USD", "level": 1, "multiplier": 2783138807808000, "price": "0.04", "resource": "msd", "unit": "GB/30 days"},
+		{"currency": "USD", "level": 0, "multiplier": 2592000, "price": "5", "resource": "ip", "unit": "IP/30 days"},
+		{"currency": "USD", "level": 1, "multiplier": 2592000, "price": "4", "resource": "ip", "unit": "IP/30 days"},
+		{"currency": "USD", "level": 0, "multiplier": 2592000, "price": "20", "resource": "msft_lwa_00135", "unit": "count/30 days"}
+	]
+}`
+
+func TestPricing_Get(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/pricing/", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "0", r.URL.Query().Get("limit"))
+		_, _ = fmt.Fprint(w, pricingJSON)
+	})
+
+	pricing, resp, err := client.Pricing.Get(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 13, resp.Meta.TotalCount)
+	assert.Len(t, pricing.Prices, 13)
+	assert.Equal(t, map[string]int{"cpu": 1, "mem": 1, "dssd": 2}, pricing.Current)
+	assert.Equal(t, Price{
+		Currency:   "USD",
+		Level:      0,
+		Multiplier: 2592000000,
+		Price:      MustParseDecimal("20"),
+		Resource:   "cpu",
+		Unit:       "GHz/30 days",
+	}, pricing.Prices[0])
+}
+
+func TestPricing_prices(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/pricing/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, pricingJSON)
+	})
+	pricing, _, _ := client.Pricing.Get(ctx)
+
+	burst, ok := pricing.BurstPrice("cpu", "usd")
+	assert.True(t, ok)
+	assert.Equal(t, "20", burst.Price.String())
+
+	current, ok := pricing.CurrentPrice("cpu", "USD")
+	assert.True(t, ok)
+	assert.Equal(t, 1, current.Level)
+
+	current, ok = pricing.CurrentPrice("msd", "USD")
+	assert.True(t, ok)
+	assert.Equal(t, 1, current.Level, "lowest subscription level without current level")
+
+	current, ok = pricing.CurrentPrice("msft_lwa_00135", "USD")
+	assert.True(t, ok)
+	assert.Equal(t, 0, current.Level, "burst price without subscription prices")
+
+	next, ok := pricing.NextPrice("cpu", "USD")
+	assert.True(t, ok)
+	assert.Equal(t, "9", next.Price.String())
+
+	_, ok = pricing.NextPrice("mem", "USD")
+	assert.False(t, ok)
+
+	_, ok = pricing.BurstPrice("cpu", "EUR")
+	assert.False(t, ok)
+}
+
+func TestPrice_Cost(t *testing.T) {
+	price := Price{Multiplier: 2592000000, Price: MustParseDecimal("10")}
+
+	assert.Equal(t, 0, price.Cost(2000, secondsPerMonth).Cmp(MustParseDecimal("20")))
+	assert.Equal(t, 0, price.Cost(720, secondsPerHour).Cmp(MustParseDecimal("0.01")))
+	assert.True(t, (&Price{}).Cost(1, 1).IsZero())
+}
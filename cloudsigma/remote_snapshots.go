@@ -2,8 +2,12 @@ package cloudsigma
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
+	"time"
 )
 
 const remoteSnapshotsBasePath = "remotesnapshots"
@@ -19,13 +23,38 @@ type RemoteSnapshot struct {
 	Snapshot
 }
 
+// MarshalJSON is a custom marshaller for RemoteSnapshot. Without it the
+// promoted Snapshot.MarshalJSON would drop Location and
+// RemoteSnapshotDriveMetadata from the payload.
+func (r *RemoteSnapshot) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(&r.Snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	if r.Location != "" {
+		fields["location"], _ = json.Marshal(r.Location)
+	}
+	if r.RemoteSnapshotDriveMetadata != nil {
+		fields["drive_meta"], err = json.Marshal(r.RemoteSnapshotDriveMetadata)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(fields)
+}
+
 // RemoteSnapshotDriveMetadata represents a CloudSigma snapshot drive meta.
 type RemoteSnapshotDriveMetadata struct {
-	Media       string `json:"media,omitempty"`
-	Name        string `json:"name,omitempty"`
-	Size        int    `json:"size,omitempty"`
-	SourceUUID  string `json:"src_uuid,omitempty"`
-	StorageType string `json:"storage_type,omitempty"`
+	Media       DriveMedia  `json:"media,omitempty"`
+	Name        string      `json:"name,omitempty"`
+	Size        int         `json:"size,omitempty"`
+	SourceUUID  string      `json:"src_uuid,omitempty"`
+	StorageType StorageType `json:"storage_type,omitempty"`
 }
 
 // RemoteSnapshotCreateRequest represents a request to create a remote snapshot.
@@ -38,6 +67,60 @@ type RemoteSnapshotUpdateRequest struct {
 	*RemoteSnapshot
 }
 
+// ReplicateOptions specifies the optional parameters to the
+// RemoteSnapshotsService.Replicate.
+type ReplicateOptions struct {
+	// Name of the remote snapshot.
+	Name string
+	// Meta of the remote snapshot.
+	Meta map[string]interface{}
+	// Tags of the remote snapshot.
+	Tags []Tag
+	// Progress is called after every status check of the remote snapshot.
+	Progress func(ReplicationProgress)
+}
+
+// ReplicationProgress represents the status of a remote snapshot during
+// replication. A status is empty if the remote snapshot is not (yet) visible
+// at that location.
+type ReplicationProgress struct {
+	Elapsed        time.Duration
+	SourceLocation string
+	SourceStatus   string
+	TargetLocation string
+	TargetStatus   string
+}
+
+// Replication represents a completed replication of a drive. Source and
+// Target are the remote snapshot as seen by the source and target locations.
+type Replication struct {
+	Source *RemoteSnapshot
+	Target *RemoteSnapshot
+}
+
+// Errors returned by RemoteSnapshotsService.Replicate.
+var (
+	// ErrSameLocation is returned when the target location is the location
+	// of the source drive.
+	ErrSameLocation = errors.New("cloudsigma-sdk-go: target location must differ from source location")
+
+	// ErrDriveNotSnapshotable is returned when the runtime of a drive reports
+	// it cannot be snapshotted.
+	ErrDriveNotSnapshotable = errors.New("cloudsigma-sdk-go: drive is not snapshotable")
+
+	// ErrReplicationFailed is returned when a remote snapshot reaches a
+	// failed status.
+	ErrReplicationFailed = errors.New("cloudsigma-sdk-go: replication failed")
+)
+
+const remoteSnapshotAvailableStatus = "available"
+
+// remoteSnapshotFailedStatuses lists statuses of failed remote snapshots.
+var remoteSnapshotFailedStatuses = map[string]bool{
+	"error":  true,
+	"failed": true,
+}
+
 type remoteSnapshotsRoot struct {
 	RemoteSnapshots []RemoteSnapshot `json:"objects"`
 	Meta            *Meta            `json:"meta,omitempty"`
@@ -157,3 +240,239 @@ func (s *RemoteSnapshotsService) Delete(ctx context.Context, uuid string) (*Resp
 
 	return s.client.Do(ctx, req, nil)
 }
+
+// Replicate copies a drive identified by driveUUID to targetLocation. It
+// creates a remote snapshot of the drive, and polls its status at the
+// source and target locations until the remote snapshot is available at
+// both. ReplicateOptions is optional. Use a context with a deadline to limit
+// the waiting time.
+//
+// Replicate fails fast with ErrSameLocation if targetLocation is the
+// location of the client, and with ErrDriveNotSnapshotable if the drive
+// cannot be snapshotted.
+func (s *RemoteSnapshotsService) Replicate(ctx context.Context, driveUUID, targetLocation string, opts *ReplicateOptions) (*Replication, error) {
+	if driveUUID == "" || targetLocation == "" {
+		return nil, ErrEmptyArgument
+	}
+	if strings.EqualFold(targetLocation, s.client.Location()) {
+		return nil, ErrSameLocation
+	}
+	if opts == nil {
+		opts = &ReplicateOptions{}
+	}
+
+	drive, _, err := (*DrivesService)(s).Get(ctx, driveUUID)
+	if err != nil {
+		return nil, err
+	}
+	if drive.Runtime == nil || !drive.Runtime.IsSnapshotable {
+		return nil, ErrDriveNotSnapshotable
+	}
+
+	createRequest := &RemoteSnapshotCreateRequest{
+		RemoteSnapshots: []RemoteSnapshot{
+			{
+				Location: targetLocation,
+				Snapshot: Snapshot{
+					Drive: &Drive{UUID: driveUUID},
+					Meta:  opts.Meta,
+					Name:  opts.Name,
+					Tags:  opts.Tags,
+				},
+			},
+		},
+	}
+	created, _, err := s.Create(ctx, createRequest)
+	if err != nil {
+		return nil, err
+	}
+	if len(created) == 0 {
+		return nil, ErrResourceNotFound
+	}
+	uuid := created[0].UUID
+
+	target := s.client.ForLocation(targetLocation).RemoteSnapshots
+	replication := new(Replication)
+	started := time.Now()
+	err = s.client.poll(ctx, func() (bool, error) {
+		source, _, err := s.Get(ctx, uuid)
+		if err != nil {
+			return false, err
+		}
+		replication.Source = source
+
+		replica, resp, err := target.Get(ctx, uuid)
+		switch {
+		case err == nil:
+			replication.Target = replica
+		case resp != nil && resp.StatusCode == http.StatusNotFound:
+			replication.Target = nil
+		default:
+			return false, err
+		}
+
+		progress := ReplicationProgress{
+			Elapsed:        time.Since(started),
+			SourceLocation: s.client.Location(),
+			SourceStatus:   source.Status,
+			TargetLocation: targetLocation,
+		}
+		if replication.Target != nil {
+			progress.TargetStatus = replication.Target.Status
+		}
+		if opts.Progress != nil {
+			opts.Progress(progress)
+		}
+
+		if remoteSnapshotFailedStatuses[progress.SourceStatus] {
+			return false, fmt.Errorf("%w: remote snapshot %v is %v at %v", ErrReplicationFailed, uuid, progress.SourceStatus, progress.SourceLocation)
+		}
+		if remoteSnapshotFailedStatuses[progress.TargetStatus] {
+			return false, fmt.Errorf("%w: remote snapshot %v is %v at %v", ErrReplicationFailed, uuid, progress.TargetStatus, progress.TargetLocation)
+		}
+		return progress.SourceStatus == remoteSnapshotAvailableStatus && progress.TargetStatus == remoteSnapshotAvailableStatus, nil
+	})
+	if err != nil {
+		return replication, err
+	}
+
+	return replication, nil
+}
+
+// Restore creates a new drive from a remote snapshot. The request is sent to
+// the API of the remote snapshot location, so the drive is created there even
+// if the location of the client is not available. Fields of
+// SnapshotCloneRequest are optional and default to the values of
+// RemoteSnapshotDriveMetadata.
+func (s *RemoteSnapshotsService) Restore(ctx context.Context, remoteSnapshot *RemoteSnapshot, cloneRequest *SnapshotCloneRequest) (*Drive, *Response, error) {
+	if remoteSnapshot == nil || remoteSnapshot.UUID == "" {
+		return nil, nil, ErrEmptyArgument
+	}
+
+	request := SnapshotCloneRequest{}
+	if cloneRequest != nil {
+		request = *cloneRequest
+	}
+	if m := remoteSnapshot.RemoteSnapshotDriveMetadata; m != nil {
+		if request.Media == "" {
+			request.Media = m.Media
+		}
+		if request.Name == "" {
+			request.Name = m.Name
+		}
+		if request.Size == 0 {
+			request.Size = m.Size
+		}
+		if request.StorageType == "" {
+			request.StorageType = m.StorageType
+		}
+	}
+
+	c := s.client
+	if l := strings.ToLower(remoteSnapshot.Location); l != "" && l != strings.ToLower(c.Location()) {
+		c = c.ForLocation(l)
+	}
+
+	path := fmt.Sprintf("%v/%v/action/?do=clone", remoteSnapshotsBasePath, remoteSnapshot.UUID)
+
+	req, err := c.NewRequest(http.MethodPost, path, request)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	drive := new(Drive)
+	resp, err := c.Do(ctx, req, drive)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return drive, resp, nil
+}
+
+// RestoreServer returns a server definition for ServersService.Create based
+// on an existing server definition, with its drives replaced by restored
+// drives. restored maps the uuid of an original drive (the source uuid of a
+// remote snapshot) to the drive restored from it. CD-ROM drives without a
+// restored drive are omitted, while a missing disk causes an error.
+//
+// Identifiers and runtime information of the original server are removed.
+// Public IPs, VLANs, firewall policies, tags and public keys belong to a
+// location, so NICs with a static IP are changed to DHCP, NICs attached to a
+// VLAN are omitted, and firewall policies, tags and public keys are removed.
+// Meta and other lists are copied, so the original server is never changed.
+func RestoreServer(server *Server, restored map[string]*Drive) (*Server, error) {
+	if server == nil {
+		return nil, ErrEmptyArgument
+	}
+
+	restoredServer := *server
+	restoredServer.Owner = nil
+	restoredServer.ResourceURI = ""
+	restoredServer.Runtime = nil
+	restoredServer.Status = ""
+	restoredServer.UUID = ""
+
+	// copy maps and slices, so changes of the restored server do not
+	// change the original one
+	restoredServer.Meta = nil
+	if server.Meta != nil {
+		restoredServer.Meta = make(map[string]interface{}, len(server.Meta))
+		for key, value := range server.Meta {
+			restoredServer.Meta[key] = value
+		}
+	}
+	restoredServer.Extra = nil
+	if server.Extra != nil {
+		restoredServer.Extra = make(map[string]json.RawMessage, len(server.Extra))
+		for key, value := range server.Extra {
+			restoredServer.Extra[key] = value
+		}
+	}
+	restoredServer.EnclavePageCaches = append([]EnclavePageCache(nil), server.EnclavePageCaches...)
+	restoredServer.PublicKeys = nil
+	restoredServer.Tags = nil
+
+	restoredServer.Drives = nil
+	var missing []string
+	for _, sd := range server.Drives {
+		if sd.Drive == nil {
+			continue
+		}
+		drive, ok := restored[sd.Drive.UUID]
+		if !ok || drive == nil {
+			if sd.Drive.Media != DriveMediaCDROM {
+				missing = append(missing, sd.Drive.UUID)
+			}
+			continue
+		}
+		sd.Drive = &Drive{UUID: drive.UUID}
+		restoredServer.Drives = append(restoredServer.Drives, sd)
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("cloudsigma-sdk-go: no restored drive for %v", strings.Join(missing, ", "))
+	}
+
+	restoredServer.NICs = nil
+	for _, nic := range server.NICs {
+		if nic.VLAN != nil {
+			continue
+		}
+		nic.FirewallPolicy = nil
+		nic.MACAddress = ""
+		nic.IP4Configuration = restoredIPConfiguration(nic.IP4Configuration)
+		nic.IP6Configuration = restoredIPConfiguration(nic.IP6Configuration)
+		restoredServer.NICs = append(restoredServer.NICs, nic)
+	}
+
+	return &restoredServer, nil
+}
+
+func restoredIPConfiguration(conf *ServerIPConfiguration) *ServerIPConfiguration {
+	if conf == nil {
+		return nil
+	}
+	if conf.Type == IPConfigurationStatic {
+		return &ServerIPConfiguration{Type: IPConfigurationDHCP}
+	}
+	return &ServerIPConfiguration{Type: conf.Type}
+}
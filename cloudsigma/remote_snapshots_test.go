@@ -188,3 +188,243 @@ func TestRemoteSnapshots_Delete_emptyUUID(t *testing.T) {
 	assert.Error(t, err)
 	assert.Equal(t, ErrEmptyArgument.Error(), err.Error())
 }
+
+func TestRemoteSnapshots_MarshalJSON(t *testing.T) {
+	remoteSnapshot := &RemoteSnapshot{
+		Location: "fra",
+		RemoteSnapshotDriveMetadata: &RemoteSnapshotDriveMetadata{
+			Media: "disk",
+		},
+		Snapshot: Snapshot{Name: "test snapshot"},
+	}
+
+	data, err := json.Marshal(remoteSnapshot)
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"drive_meta":{"media":"disk"},"location":"fra","name":"test snapshot","tags":[]}`, string(data))
+}
+
+func TestRemoteSnapshots_Replicate(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/drives/drive-uuid/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"runtime":{"is_snapshotable":true},"uuid":"drive-uuid"}`)
+	})
+	mux.HandleFunc("/remotesnapshots/", func(w http.ResponseWriter, r *http.Request) {
+		v := new(RemoteSnapshotCreateRequest)
+		_ = json.NewDecoder(r.Body).Decode(v)
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "fra", v.RemoteSnapshots[0].Location)
+		assert.Equal(t, "drive-uuid", v.RemoteSnapshots[0].Drive.UUID)
+		assert.Equal(t, "nightly", v.RemoteSnapshots[0].Name)
+		_, _ = fmt.Fprint(w, `{"objects":[{"location":"fra","status":"creating","uuid":"snapshot-uuid"}]}`)
+	})
+	polls := 0
+	mux.HandleFunc("/remotesnapshots/snapshot-uuid/", func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		status := "creating"
+		if polls > 2 {
+			status = "available"
+		}
+		_, _ = fmt.Fprintf(w, `{"location":"fra","status":%q,"uuid":"snapshot-uuid"}`, status)
+	})
+	targetPolls := 0
+	mux.HandleFunc("/fra/remotesnapshots/snapshot-uuid/", func(w http.ResponseWriter, r *http.Request) {
+		targetPolls++
+		if targetPolls < 2 {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = fmt.Fprint(w, `[{"error_message":"not found","error_type":"notexist"}]`)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"location":"fra","status":"available","uuid":"snapshot-uuid"}`)
+	})
+	var progress []ReplicationProgress
+	opts := &ReplicateOptions{
+		Name:     "nightly",
+		Progress: func(p ReplicationProgress) { progress = append(progress, p) },
+	}
+
+	replication, err := client.RemoteSnapshots.Replicate(ctx, "drive-uuid", "fra", opts)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "available", replication.Source.Status)
+	assert.Equal(t, "available", replication.Target.Status)
+	assert.Len(t, progress, 3)
+	assert.Equal(t, "", progress[0].TargetStatus)
+	assert.Equal(t, "creating", progress[1].SourceStatus)
+	assert.Equal(t, "available", progress[1].TargetStatus)
+	assert.Equal(t, "fra", progress[2].TargetLocation)
+}
+
+func TestRemoteSnapshots_Replicate_failed(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/drives/drive-uuid/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"runtime":{"is_snapshotable":true},"uuid":"drive-uuid"}`)
+	})
+	mux.HandleFunc("/remotesnapshots/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"objects":[{"uuid":"snapshot-uuid"}]}`)
+	})
+	mux.HandleFunc("/remotesnapshots/snapshot-uuid/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"status":"creating","uuid":"snapshot-uuid"}`)
+	})
+	mux.HandleFunc("/fra/remotesnapshots/snapshot-uuid/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"status":"failed","uuid":"snapshot-uuid"}`)
+	})
+
+	replication, err := client.RemoteSnapshots.Replicate(ctx, "drive-uuid", "fra", nil)
+
+	assert.ErrorIs(t, err, ErrReplicationFailed)
+	assert.Equal(t, "failed", replication.Target.Status)
+}
+
+func TestRemoteSnapshots_Replicate_notSnapshotable(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/drives/drive-uuid/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"runtime":{"is_snapshotable":false},"uuid":"drive-uuid"}`)
+	})
+
+	_, err := client.RemoteSnapshots.Replicate(ctx, "drive-uuid", "fra", nil)
+
+	assert.ErrorIs(t, err, ErrDriveNotSnapshotable)
+}
+
+func TestRemoteSnapshots_Replicate_sameLocation(t *testing.T) {
+	_, err := client.RemoteSnapshots.Replicate(ctx, "drive-uuid", "ZRH", nil)
+
+	assert.ErrorIs(t, err, ErrSameLocation)
+}
+
+func TestRemoteSnapshots_Replicate_emptyArguments(t *testing.T) {
+	_, err := client.RemoteSnapshots.Replicate(ctx, "", "fra", nil)
+
+	assert.Error(t, err)
+	assert.Equal(t, ErrEmptyArgument.Error(), err.Error())
+}
+
+func TestRemoteSnapshots_Restore(t *testing.T) {
+	setup()
+	defer teardown()
+
+	remoteSnapshot := &RemoteSnapshot{
+		Location: "FRA",
+		RemoteSnapshotDriveMetadata: &RemoteSnapshotDriveMetadata{
+			Media:       "disk",
+			Name:        "original drive",
+			Size:        3221225472, // 3GB
+			SourceUUID:  "original-uuid",
+			StorageType: "dssd",
+		},
+		Snapshot: Snapshot{UUID: "snapshot-uuid"},
+	}
+	mux.HandleFunc("/fra/remotesnapshots/snapshot-uuid/action/", func(w http.ResponseWriter, r *http.Request) {
+		v := new(SnapshotCloneRequest)
+		_ = json.NewDecoder(r.Body).Decode(v)
+		assert.Equal(t, &SnapshotCloneRequest{Media: "disk", Name: "restored drive", Size: 3221225472, StorageType: "dssd"}, v)
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "clone", r.URL.Query().Get("do"))
+		_, _ = fmt.Fprint(w, `{"name":"restored drive","uuid":"restored-uuid"}`)
+	})
+	expected := &Drive{
+		Name: "restored drive",
+		UUID: "restored-uuid",
+	}
+
+	drive, _, err := client.RemoteSnapshots.Restore(ctx, remoteSnapshot, &SnapshotCloneRequest{Name: "restored drive"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, drive)
+}
+
+func TestRemoteSnapshots_Restore_emptySnapshot(t *testing.T) {
+	_, _, err := client.RemoteSnapshots.Restore(ctx, nil, nil)
+
+	assert.Error(t, err)
+	assert.Equal(t, ErrEmptyArgument.Error(), err.Error())
+}
+
+func TestRemoteSnapshots_RestoreServer(t *testing.T) {
+	server := &Server{
+		CPU:    2000,
+		Memory: 536870912,
+		Name:   "web",
+		Drives: []ServerDrive{
+			{BootOrder: 1, DevChannel: "0:0", Device: "virtio", Drive: &Drive{UUID: "disk-uuid"}},
+			{DevChannel: "0:1", Device: "ide", Drive: &Drive{Media: "cdrom", UUID: "cdrom-uuid"}},
+		},
+		NICs: []ServerNIC{
+			{IP4Configuration: &ServerIPConfiguration{Type: "static", IPAddress: &IP{UUID: "1.2.3.4"}}, MACAddress: "22:aa", Model: "virtio"},
+			{VLAN: &VLAN{UUID: "vlan-uuid"}},
+		},
+		Runtime: &ServerRuntime{},
+		Status:  "running",
+		UUID:    "server-uuid",
+	}
+	restored := map[string]*Drive{"disk-uuid": {UUID: "restored-uuid"}}
+	expected := &Server{
+		CPU:    2000,
+		Memory: 536870912,
+		Name:   "web",
+		Drives: []ServerDrive{
+			{BootOrder: 1, DevChannel: "0:0", Device: "virtio", Drive: &Drive{UUID: "restored-uuid"}},
+		},
+		NICs: []ServerNIC{
+			{IP4Configuration: &ServerIPConfiguration{Type: "dhcp"}, Model: "virtio"},
+		},
+	}
+
+	restoredServer, err := RestoreServer(server, restored)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, restoredServer)
+	assert.Equal(t, "server-uuid", server.UUID)
+	assert.Equal(t, "disk-uuid", server.Drives[0].Drive.UUID)
+}
+
+func TestRemoteSnapshots_RestoreServer_copy(t *testing.T) {
+	server := &Server{
+		EnclavePageCaches: []EnclavePageCache{{Size: 1024}},
+		Meta:              map[string]interface{}{"role": "web"},
+	}
+
+	restoredServer, err := RestoreServer(server, nil)
+	assert.NoError(t, err)
+	restoredServer.EnclavePageCaches[0].Size = 2048
+	restoredServer.Meta["role"] = "db"
+
+	assert.Equal(t, []EnclavePageCache{{Size: 1024}}, server.EnclavePageCaches)
+	assert.Equal(t, map[string]interface{}{"role": "web"}, server.Meta)
+}
+
+func TestRemoteSnapshots_RestoreServer_locationResources(t *testing.T) {
+	server := &Server{
+		NICs: []ServerNIC{
+			{FirewallPolicy: &FirewallPolicy{UUID: "policy-uuid"}, IP4Configuration: &ServerIPConfiguration{Type: "dhcp"}},
+		},
+		PublicKeys: []Keypair{{UUID: "key-uuid"}},
+		Tags:       []Tag{{UUID: "tag-uuid"}},
+	}
+
+	restoredServer, err := RestoreServer(server, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []ServerNIC{{IP4Configuration: &ServerIPConfiguration{Type: "dhcp"}}}, restoredServer.NICs)
+	assert.Nil(t, restoredServer.PublicKeys)
+	assert.Nil(t, restoredServer.Tags)
+	assert.Equal(t, "policy-uuid", server.NICs[0].FirewallPolicy.UUID)
+	assert.Equal(t, []Tag{{UUID: "tag-uuid"}}, server.Tags)
+}
+
+func TestRemoteSnapshots_RestoreServer_missingDrive(t *testing.T) {
+	server := &Server{
+		Drives: []ServerDrive{{Drive: &Drive{UUID: "disk-uuid"}}},
+	}
+
+	_, err := RestoreServer(server, nil)
+
+	assert.EqualError(t, err, "cloudsigma-sdk-go: no restored drive for disk-uuid")
+}
@@ -0,0 +1,278 @@
+package cloudsigma
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultCapabilitiesTTL is the default time capabilities are cached by a
+// ServerValidator.
+const DefaultCapabilitiesTTL = time.Hour
+
+const (
+	hostTypeAMD   = "amd"
+	hostTypeIntel = "intel"
+)
+
+// FieldError describes an invalid value of a single field. Field is the JSON
+// path of the field, e.g. "cpu" or "epcs[0].size".
+type FieldError struct {
+	Field   string
+	Value   interface{}
+	Message string
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%v: %v", e.Field, e.Message)
+}
+
+// ValidationError lists invalid fields of a resource definition.
+type ValidationError struct {
+	Fields []*FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		messages = append(messages, f.Error())
+	}
+	return fmt.Sprintf("cloudsigma-sdk-go: invalid definition: %v", strings.Join(messages, "; "))
+}
+
+// Unwrap returns the field errors.
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		errs = append(errs, f)
+	}
+	return errs
+}
+
+// ValidateServer checks the server definition against capabilities. Zero
+// values are treated as not set. The server must fit the limits of at least
+// one host type it can run on, which is restricted by CPUType, Hypervisor and
+// EnclavePageCaches. A *ValidationError is returned if the server is invalid.
+func ValidateServer(server *Server, capabilities *Capabilities) error {
+	if server == nil || capabilities == nil {
+		return ErrEmptyArgument
+	}
+
+	v := &serverValidation{server: server, hosts: capabilityHosts(capabilities)}
+	v.validateHostTypes(capabilities)
+	reported := len(v.errors)
+	v.validateLimit("cpu", server.CPU, "MHz", func(h *CapabilitiesHost) *CapabilitiesLimitation { return h.CPU })
+	v.validateLimit("smp", server.SMP, "cores", func(h *CapabilitiesHost) *CapabilitiesLimitation { return h.SMP })
+	if server.SMP > 0 && len(v.errors) == reported {
+		v.validateLimit("cpu", server.CPU/server.SMP, "MHz per core", func(h *CapabilitiesHost) *CapabilitiesLimitation { return h.CPUPerSMP })
+	}
+	v.validateLimit("mem", server.Memory, "bytes", func(h *CapabilitiesHost) *CapabilitiesLimitation { return h.Memory })
+	if server.EnableNuma && server.SMP == 1 {
+		v.addError("enable_numa", server.EnableNuma, "requires smp of at least 2")
+	}
+	for i, epc := range server.EnclavePageCaches {
+		if epc.Size <= 0 {
+			v.addError(fmt.Sprintf("epcs[%d].size", i), epc.Size, "must be positive")
+		}
+	}
+
+	if len(v.errors) > 0 {
+		return &ValidationError{Fields: v.errors}
+	}
+	return nil
+}
+
+type serverValidation struct {
+	server *Server
+	hosts  map[string]*CapabilitiesHost
+	// candidates are host types the server can run on, sorted by name.
+	candidates []string
+	errors     []*FieldError
+}
+
+func (v *serverValidation) addError(field string, value interface{}, format string, args ...interface{}) {
+	v.errors = append(v.errors, &FieldError{Field: field, Value: value, Message: fmt.Sprintf(format, args...)})
+}
+
+// validateHostTypes restricts the candidate host types by CPU type,
+// hypervisor and enclave page caches, which are available on Intel only.
+func (v *serverValidation) validateHostTypes(capabilities *Capabilities) {
+	for hostType := range v.hosts {
+		v.candidates = append(v.candidates, hostType)
+	}
+	sort.Strings(v.candidates)
+
+	if cpuType := v.server.CPUType; cpuType != "" {
+		if _, ok := v.hosts[cpuType]; !ok {
+			v.addError("cpu_type", cpuType, "must be one of %v", strings.Join(v.candidates, ", "))
+			return
+		}
+		v.candidates = []string{cpuType}
+	}
+
+	if hypervisor := v.server.Hypervisor; hypervisor != "" {
+		available, ok := capabilityHypervisors(capabilities)[hypervisor]
+		if !ok {
+			v.addError("hypervisor", hypervisor, "is not available")
+			v.candidates = nil
+			return
+		}
+		v.restrict("hypervisor", hypervisor, available)
+	}
+
+	if len(v.server.EnclavePageCaches) > 0 {
+		v.restrict("epcs", len(v.server.EnclavePageCaches), []string{hostTypeIntel})
+	}
+}
+
+// restrict keeps only candidate host types listed in allowed, and reports
+// the field if no candidate is left.
+func (v *serverValidation) restrict(field string, value interface{}, allowed []string) {
+	if len(v.candidates) == 0 {
+		return
+	}
+	var candidates []string
+	for _, c := range v.candidates {
+		for _, a := range allowed {
+			if c == a {
+				candidates = append(candidates, c)
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		v.addError(field, value, "is only available on %v hosts", strings.Join(allowed, ", "))
+	}
+	v.candidates = candidates
+}
+
+// validateLimit reports the field if the value does not fit the limitation
+// of any candidate host type. Zero values and missing limitations are
+// skipped.
+func (v *serverValidation) validateLimit(field string, value int, unit string, limitation func(*CapabilitiesHost) *CapabilitiesLimitation) {
+	if value == 0 || len(v.candidates) == 0 {
+		return
+	}
+
+	var ranges []string
+	for _, hostType := range v.candidates {
+		l := limitation(v.hosts[hostType])
+		if l == nil || (value >= l.Min && (l.Max == 0 || value <= l.Max)) {
+			return
+		}
+		ranges = append(ranges, fmt.Sprintf("%d-%d on %v hosts", l.Min, l.Max, hostType))
+	}
+	v.addError(field, value, "%d %v is out of range %v", value, unit, strings.Join(ranges, ", "))
+}
+
+func capabilityHosts(capabilities *Capabilities) map[string]*CapabilitiesHost {
+	hosts := make(map[string]*CapabilitiesHost)
+	if h := capabilities.Hosts; h != nil {
+		if h.AMD != nil {
+			hosts[hostTypeAMD] = h.AMD
+		}
+		if h.Intel != nil {
+			hosts[hostTypeIntel] = h.Intel
+		}
+	}
+	return hosts
+}
+
+func capabilityHypervisors(capabilities *Capabilities) map[string][]string {
+	hypervisors := make(map[string][]string)
+	if h := capabilities.Hypervisors; h != nil && h.KVM != nil {
+		hypervisors["kvm"] = h.KVM
+	}
+	return hypervisors
+}
+
+// ServerValidator validates server definitions against the capabilities of
+// the client location. Capabilities are cached per location. It is safe for
+// concurrent use.
+type ServerValidator struct {
+	ttl time.Duration
+
+	mu    sync.Mutex
+	cache map[string]cachedCapabilities
+}
+
+type cachedCapabilities struct {
+	capabilities *Capabilities
+	expires      time.Time
+}
+
+// NewServerValidator returns a ServerValidator caching capabilities for ttl.
+// A zero ttl defaults to DefaultCapabilitiesTTL.
+func NewServerValidator(ttl time.Duration) *ServerValidator {
+	if ttl == 0 {
+		ttl = DefaultCapabilitiesTTL
+	}
+	return &ServerValidator{ttl: ttl, cache: make(map[string]cachedCapabilities)}
+}
+
+// Validate checks the server definition against the capabilities of the
+// client location. See ValidateServer.
+func (v *ServerValidator) Validate(ctx context.Context, client *Client, server *Server) error {
+	capabilities, err := v.capabilities(ctx, client)
+	if err != nil {
+		return err
+	}
+	return ValidateServer(server, capabilities)
+}
+
+func (v *ServerValidator) capabilities(ctx context.Context, client *Client) (*Capabilities, error) {
+	if client == nil {
+		return nil, ErrEmptyArgument
+	}
+	key := client.baseURL.String()
+
+	v.mu.Lock()
+	cached, ok := v.cache[key]
+	v.mu.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached.capabilities, nil
+	}
+
+	capabilities, _, err := client.Capabilities.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	v.mu.Lock()
+	v.cache[key] = cachedCapabilities{capabilities: capabilities, expires: time.Now().Add(v.ttl)}
+	v.mu.Unlock()
+	return capabilities, nil
+}
+
+// validateServers validates servers with the client server validator, if
+// configured. Field paths are prefixed with "objects[i]." if prefix is set.
+func (c *Client) validateServers(ctx context.Context, servers []Server, prefix bool) error {
+	if c.serverValidator == nil {
+		return nil
+	}
+
+	var fields []*FieldError
+	for i := range servers {
+		err := c.serverValidator.Validate(ctx, c, &servers[i])
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		for _, f := range validationErr.Fields {
+			if prefix {
+				f.Field = fmt.Sprintf("objects[%d].%v", i, f.Field)
+			}
+			fields = append(fields, f)
+		}
+	}
+	if len(fields) > 0 {
+		return &ValidationError{Fields: fields}
+	}
+	return nil
+}
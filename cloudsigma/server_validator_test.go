@@ -0,0 +1,138 @@
+package cloudsigma
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const capabilitiesJSON = `{
+	"hosts": {
+		"amd": {"cpu": {"min": 250, "max": 80000}, "cpu_per_smp": {"min": 250, "max": 2500}, "mem": {"min": 268435456, "max": 137438953472}, "smp": {"min": 1, "max": 32}},
+		"intel": {"cpu": {"min": 250, "max": 100000}, "cpu_per_smp": {"min": 250, "max": 3000}, "mem": {"min": 268435456, "max": 274877906944}, "smp": {"min": 1, "max": 64}}
+	},
+	"hypervisors": {"kvm": ["amd", "intel"]}
+}`
+
+func validationFields(err error) []string {
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		return nil
+	}
+	var fields []string
+	for _, f := range validationErr.Fields {
+		fields = append(fields, f.Field)
+	}
+	return fields
+}
+
+func testCapabilities(t *testing.T) *Capabilities {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/capabilities/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, capabilitiesJSON)
+	})
+	capabilities, _, err := client.Capabilities.Get(ctx)
+	assert.NoError(t, err)
+	return capabilities
+}
+
+func TestValidateServer(t *testing.T) {
+	capabilities := testCapabilities(t)
+
+	err := ValidateServer(&Server{CPU: 4000, Memory: 4 * gibibyte, SMP: 2, Hypervisor: "kvm", EnableNuma: true}, capabilities)
+
+	assert.NoError(t, err)
+}
+
+func TestValidateServer_limits(t *testing.T) {
+	capabilities := testCapabilities(t)
+
+	err := ValidateServer(&Server{CPU: 100, Memory: 512 * gibibyte, SMP: 100}, capabilities)
+
+	assert.Equal(t, []string{"cpu", "smp", "mem"}, validationFields(err))
+	assert.EqualError(t, err, "cloudsigma-sdk-go: invalid definition: "+
+		"cpu: 100 MHz is out of range 250-80000 on amd hosts, 250-100000 on intel hosts; "+
+		"smp: 100 cores is out of range 1-32 on amd hosts, 1-64 on intel hosts; "+
+		"mem: 549755813888 bytes is out of range 268435456-137438953472 on amd hosts, 268435456-274877906944 on intel hosts")
+}
+
+func TestValidateServer_hostType(t *testing.T) {
+	capabilities := testCapabilities(t)
+
+	// 90 GHz fit Intel hosts only
+	assert.NoError(t, ValidateServer(&Server{CPU: 90000, SMP: 32}, capabilities))
+	assert.Equal(t, []string{"cpu"}, validationFields(ValidateServer(&Server{CPU: 90000, CPUType: "amd"}, capabilities)))
+	// 3 GHz per core fit Intel hosts only
+	assert.Equal(t, []string{"cpu"}, validationFields(ValidateServer(&Server{CPU: 6000, SMP: 2, CPUType: "amd"}, capabilities)))
+	assert.Equal(t, []string{"cpu_type"}, validationFields(ValidateServer(&Server{CPUType: "arm"}, capabilities)))
+	assert.Equal(t, []string{"hypervisor"}, validationFields(ValidateServer(&Server{Hypervisor: "xen"}, capabilities)))
+}
+
+func TestValidateServer_enclavePageCaches(t *testing.T) {
+	capabilities := testCapabilities(t)
+
+	err := ValidateServer(&Server{CPUType: "amd", EnclavePageCaches: []EnclavePageCache{{Size: 0}}}, capabilities)
+
+	assert.Equal(t, []string{"epcs", "epcs[0].size"}, validationFields(err))
+	assert.NoError(t, ValidateServer(&Server{EnclavePageCaches: []EnclavePageCache{{Size: 1024}}}, capabilities))
+}
+
+func TestValidateServer_numa(t *testing.T) {
+	capabilities := testCapabilities(t)
+
+	err := ValidateServer(&Server{SMP: 1, EnableNuma: true}, capabilities)
+
+	assert.Equal(t, []string{"enable_numa"}, validationFields(err))
+}
+
+func TestServers_Create_validated(t *testing.T) {
+	setup()
+	defer teardown()
+
+	calls := 0
+	mux.HandleFunc("/capabilities/", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		_, _ = fmt.Fprint(w, capabilitiesJSON)
+	})
+	mux.HandleFunc("/servers/", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("invalid server must not be sent")
+	})
+	WithServerValidator(NewServerValidator(time.Minute))(client)
+	createRequest := &ServerCreateRequest{
+		Servers: []Server{{CPU: 2000, Memory: gibibyte}, {CPU: 64000, Memory: gibibyte, SMP: 128}},
+	}
+
+	_, _, err := client.Servers.Create(ctx, createRequest)
+	assert.Equal(t, []string{"objects[1].smp"}, validationFields(err))
+
+	_, _, err = client.Servers.Update(ctx, "long-uuid", &ServerUpdateRequest{Server: &Server{CPU: 10}})
+	assert.Equal(t, []string{"cpu"}, validationFields(err))
+
+	assert.Equal(t, 1, calls, "capabilities are cached")
+}
+
+func TestServerValidator_perLocation(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var paths []string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		_, _ = fmt.Fprint(w, capabilitiesJSON)
+	}
+	mux.HandleFunc("/capabilities/", handler)
+	mux.HandleFunc("/fra/capabilities/", handler)
+	validator := NewServerValidator(0)
+
+	assert.NoError(t, validator.Validate(ctx, client, &Server{CPU: 2000}))
+	assert.NoError(t, validator.Validate(ctx, client.ForLocation("fra"), &Server{CPU: 2000}))
+	assert.NoError(t, validator.Validate(ctx, client, &Server{CPU: 2000}))
+
+	assert.Equal(t, []string{"/capabilities/", "/fra/capabilities/"}, paths)
+}
@@ -2,10 +2,20 @@ package cloudsigma
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 )
 
+// deviceChannels specifies the number of controllers and units per
+// controller available for a drive device type.
+var deviceChannels = map[string]struct{ controllers, units int }{
+	"ide":    {controllers: 2, units: 2},
+	"sata":   {controllers: 1, units: 6},
+	"scsi":   {controllers: 1, units: 16},
+	"virtio": {controllers: 4, units: 8},
+}
+
 const serversBasePath = "servers"
 
 // ServersService handles communication with the servers related methods of
@@ -34,10 +44,102 @@ type Server struct {
 	ResourceURI        string                 `json:"resource_uri,omitempty"`
 	Runtime            *ServerRuntime         `json:"runtime,omitempty"`
 	SMP                int                    `json:"smp,omitempty"`
-	Status             string                 `json:"status,omitempty"`
+	Status             ServerStatus           `json:"status,omitempty"`
 	Tags               []Tag                  `json:"tags,omitempty"`
 	UUID               string                 `json:"uuid,omitempty"`
 	VNCPassword        string                 `json:"vnc_password,omitempty"`
+
+	// Extra holds the fields returned by the API which are unknown to the
+	// SDK. They are sent back on update, so read-modify-write does not
+	// drop them.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// MarshalJSON is a custom marshaller for Server. It adds the fields of Extra.
+func (s Server) MarshalJSON() ([]byte, error) {
+	type Alias Server
+	return marshalWithExtra(Alias(s), s.Extra)
+}
+
+// UnmarshalJSON is a custom unmarshaller for Server. It keeps the fields
+// unknown to the SDK in Extra.
+func (s *Server) UnmarshalJSON(data []byte) error {
+	type Alias Server
+	extra, err := unmarshalWithExtra(data, (*Alias)(s))
+	if err != nil {
+		return err
+	}
+	s.Extra = extra
+	return nil
+}
+
+// ServerStatus represents the status of a server. Statuses unknown to the
+// SDK are kept as they are.
+type ServerStatus string
+
+// Server statuses.
+const (
+	ServerStatusPaused      ServerStatus = "paused"
+	ServerStatusRunning     ServerStatus = "running"
+	ServerStatusStarting    ServerStatus = "starting"
+	ServerStatusStopped     ServerStatus = "stopped"
+	ServerStatusStopping    ServerStatus = "stopping"
+	ServerStatusUnavailable ServerStatus = "unavailable"
+)
+
+// IsValid reports whether the status is known to the SDK.
+func (s ServerStatus) IsValid() bool {
+	switch s {
+	case ServerStatusPaused, ServerStatusRunning, ServerStatusStarting,
+		ServerStatusStopped, ServerStatusStopping, ServerStatusUnavailable:
+		return true
+	}
+	return false
+}
+
+// IsTransitional reports whether the server is changing its status, i.e.
+// starting or stopping.
+func (s ServerStatus) IsTransitional() bool {
+	return s == ServerStatusStarting || s == ServerStatusStopping
+}
+
+// NICModel represents the emulated model of a network interface card.
+type NICModel string
+
+// Network interface card models.
+const (
+	NICModelE1000   NICModel = "e1000"
+	NICModelRTL8139 NICModel = "rtl8139"
+	NICModelVirtio  NICModel = "virtio"
+)
+
+// IsValid reports whether the model is known to the SDK.
+func (m NICModel) IsValid() bool {
+	switch m {
+	case NICModelE1000, NICModelRTL8139, NICModelVirtio:
+		return true
+	}
+	return false
+}
+
+// IPConfigurationType represents the way a network interface card gets its
+// IP address.
+type IPConfigurationType string
+
+// IP configuration types.
+const (
+	IPConfigurationDHCP   IPConfigurationType = "dhcp"
+	IPConfigurationManual IPConfigurationType = "manual"
+	IPConfigurationStatic IPConfigurationType = "static"
+)
+
+// IsValid reports whether the type is known to the SDK.
+func (t IPConfigurationType) IsValid() bool {
+	switch t {
+	case IPConfigurationDHCP, IPConfigurationManual, IPConfigurationStatic:
+		return true
+	}
+	return false
 }
 
 // ServerDrive represents a CloudSigma drive attached to a server.
@@ -60,7 +162,7 @@ type ServerNIC struct {
 	IP4Configuration *ServerIPConfiguration `json:"ip_v4_conf,omitempty"`
 	IP6Configuration *ServerIPConfiguration `json:"ip_v6_conf,omitempty"`
 	MACAddress       string                 `json:"mac,omitempty"`
-	Model            string                 `json:"model,omitempty"`
+	Model            NICModel               `json:"model,omitempty"`
 	VLAN             *VLAN                  `json:"vlan,omitempty"`
 }
 
@@ -84,8 +186,8 @@ type ServerRuntimeIP struct {
 
 // ServerIPConfiguration represents a CloudSigma public IP configuration.
 type ServerIPConfiguration struct {
-	Type      string `json:"conf,omitempty"`
-	IPAddress *IP    `json:"ip,omitempty"`
+	Type      IPConfigurationType `json:"conf,omitempty"`
+	IPAddress *IP                 `json:"ip,omitempty"`
 }
 
 // ServerAction represents a CloudSigma server action.
@@ -105,6 +207,24 @@ type ServerUpdateRequest struct {
 	*Server
 }
 
+// MarshalJSON is a custom marshaller for ServerUpdateRequest. Without it the
+// promoted Server.MarshalJSON would be called on a nil Server.
+func (r ServerUpdateRequest) MarshalJSON() ([]byte, error) {
+	if r.Server == nil {
+		return []byte("{}"), nil
+	}
+	return r.Server.MarshalJSON()
+}
+
+// UnmarshalJSON is a custom unmarshaller for ServerUpdateRequest. Without
+// it the promoted Server.UnmarshalJSON would be called on a nil Server.
+func (r *ServerUpdateRequest) UnmarshalJSON(data []byte) error {
+	if r.Server == nil {
+		r.Server = new(Server)
+	}
+	return r.Server.UnmarshalJSON(data)
+}
+
 type serversRoot struct {
 	Meta    *Meta    `json:"meta,omitempty"`
 	Servers []Server `json:"objects"`
@@ -114,8 +234,12 @@ type serversRoot struct {
 // has access.
 //
 // CloudSigma API docs: https://cloudsigma-docs.readthedocs.io/en/latest/servers.html#detailed-listing
-func (s *ServersService) List(ctx context.Context) ([]Server, *Response, error) {
+func (s *ServersService) List(ctx context.Context, opts *ListOptions) ([]Server, *Response, error) {
 	path := fmt.Sprintf("%v/detail/", serversBasePath)
+	path, err := addOptions(path, opts)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	req, err := s.client.NewRequest(http.MethodGet, path, nil)
 	if err != nil {
@@ -165,6 +289,9 @@ func (s *ServersService) Create(ctx context.Context, createRequest *ServerCreate
 	if createRequest == nil {
 		return nil, nil, ErrEmptyPayloadNotAllowed
 	}
+	if err := s.client.validateServers(ctx, createRequest.Servers, true); err != nil {
+		return nil, nil, err
+	}
 
 	path := fmt.Sprintf("%v/", serversBasePath)
 
@@ -192,9 +319,12 @@ func (s *ServersService) Update(ctx context.Context, uuid string, updateRequest
 	if uuid == "" {
 		return nil, nil, ErrEmptyArgument
 	}
-	if updateRequest == nil {
+	if updateRequest == nil || updateRequest.Server == nil {
 		return nil, nil, ErrEmptyPayloadNotAllowed
 	}
+	if err := s.client.validateServers(ctx, []Server{*updateRequest.Server}, false); err != nil {
+		return nil, nil, err
+	}
 
 	path := fmt.Sprintf("%v/%v/", serversBasePath, uuid)
 
@@ -274,3 +404,60 @@ func (s *ServersService) doAction(ctx context.Context, uuid, action string) (*Se
 
 	return serverAction, resp, nil
 }
+
+// FreeDevChannel returns the first device channel (e.g. "0:1") of the given
+// device type which is not used by any drive of the server.
+func FreeDevChannel(server *Server, device string) (string, error) {
+	if server == nil {
+		return "", ErrEmptyArgument
+	}
+	channels, ok := deviceChannels[device]
+	if !ok {
+		return "", fmt.Errorf("cloudsigma-sdk-go: unknown drive device %q", device)
+	}
+
+	used := make(map[string]bool, len(server.Drives))
+	for _, d := range server.Drives {
+		if d.Device == device {
+			used[d.DevChannel] = true
+		}
+	}
+	for controller := 0; controller < channels.controllers; controller++ {
+		for unit := 0; unit < channels.units; unit++ {
+			channel := fmt.Sprintf("%d:%d", controller, unit)
+			if !used[channel] {
+				return channel, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("cloudsigma-sdk-go: no free %v channel", device)
+}
+
+// AttachDrive adds a drive to the server definition on a free channel of the
+// given device type, booted after all other drives. The change is applied
+// with ServersService.Update.
+func AttachDrive(server *Server, drive *Drive, device string) error {
+	if server == nil || drive == nil || drive.UUID == "" {
+		return ErrEmptyArgument
+	}
+
+	channel, err := FreeDevChannel(server, device)
+	if err != nil {
+		return err
+	}
+
+	bootOrder := 0
+	for _, d := range server.Drives {
+		if d.BootOrder > bootOrder {
+			bootOrder = d.BootOrder
+		}
+	}
+
+	server.Drives = append(server.Drives, ServerDrive{
+		BootOrder:  bootOrder + 1,
+		DevChannel: channel,
+		Device:     device,
+		Drive:      &Drive{UUID: drive.UUID},
+	})
+	return nil
+}
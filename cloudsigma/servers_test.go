@@ -24,7 +24,7 @@ func TestServers_List(t *testing.T) {
 		},
 	}
 
-	servers, resp, err := client.Servers.List(ctx)
+	servers, resp, err := client.Servers.List(ctx, nil)
 
 	assert.NoError(t, err)
 	assert.Equal(t, expected, servers)
@@ -122,6 +122,52 @@ func TestServers_Update(t *testing.T) {
 	assert.Equal(t, expected, server)
 }
 
+func TestServers_Update_extraFields(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/servers/long-uuid/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			_, _ = fmt.Fprint(w, `{"name":"test server","uuid":"long-uuid","grantees":["user-uuid"],"hv_relaxed":true}`)
+			return
+		}
+		v := make(map[string]interface{})
+		_ = json.NewDecoder(r.Body).Decode(&v)
+		assert.Equal(t, map[string]interface{}{
+			"name":       "test server v2",
+			"grantees":   []interface{}{"user-uuid"},
+			"hv_relaxed": true,
+		}, v)
+		_, _ = fmt.Fprint(w, `{"name":"test server v2","uuid":"long-uuid","hv_relaxed":true}`)
+	})
+	server, _, err := client.Servers.Get(ctx, "long-uuid")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]json.RawMessage{
+		"grantees":   json.RawMessage(`["user-uuid"]`),
+		"hv_relaxed": json.RawMessage(`true`),
+	}, server.Extra)
+	server.Name = "test server v2"
+
+	server, _, err = client.Servers.Update(ctx, server.UUID, &ServerUpdateRequest{Server: server})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]json.RawMessage{"hv_relaxed": json.RawMessage(`true`)}, server.Extra)
+}
+
+func TestServers_MarshalJSON(t *testing.T) {
+	server := Server{Name: "test server", Extra: map[string]json.RawMessage{"hv_relaxed": json.RawMessage(`true`)}}
+
+	data, err := json.Marshal(server)
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"name":"test server","hv_relaxed":true}`, string(data))
+
+	data, err = json.Marshal(&ServerUpdateRequest{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, `{}`, string(data))
+}
+
 func TestServers_Update_emptyUUID(t *testing.T) {
 	input := &ServerUpdateRequest{
 		Server: &Server{
@@ -257,3 +303,74 @@ func TestServer_Shutdown_emptyUUID(t *testing.T) {
 	assert.Error(t, err)
 	assert.Equal(t, ErrEmptyArgument.Error(), err.Error())
 }
+
+func TestFreeDevChannel(t *testing.T) {
+	server := &Server{
+		Drives: []ServerDrive{
+			{Device: "ide", DevChannel: "0:0"},
+			{Device: "virtio", DevChannel: "0:1"},
+			{Device: "ide", DevChannel: "0:1"},
+		},
+	}
+
+	channel, err := FreeDevChannel(server, "ide")
+	assert.NoError(t, err)
+	assert.Equal(t, "1:0", channel)
+
+	channel, err = FreeDevChannel(server, "virtio")
+	assert.NoError(t, err)
+	assert.Equal(t, "0:0", channel)
+}
+
+func TestFreeDevChannel_exhausted(t *testing.T) {
+	server := &Server{
+		Drives: []ServerDrive{
+			{Device: "ide", DevChannel: "0:0"},
+			{Device: "ide", DevChannel: "0:1"},
+			{Device: "ide", DevChannel: "1:0"},
+			{Device: "ide", DevChannel: "1:1"},
+		},
+	}
+
+	_, err := FreeDevChannel(server, "ide")
+
+	assert.Error(t, err)
+}
+
+func TestFreeDevChannel_unknownDevice(t *testing.T) {
+	_, err := FreeDevChannel(&Server{}, "floppy")
+
+	assert.Error(t, err)
+}
+
+func TestAttachDrive(t *testing.T) {
+	server := &Server{
+		Drives: []ServerDrive{
+			{BootOrder: 1, Device: "virtio", DevChannel: "0:0", Drive: &Drive{UUID: "disk-uuid"}},
+			{BootOrder: 2, Device: "ide", DevChannel: "0:0", Drive: &Drive{UUID: "cdrom-uuid"}},
+		},
+	}
+
+	err := AttachDrive(server, &Drive{Name: "seed", UUID: "seed-uuid"}, "ide")
+
+	assert.NoError(t, err)
+	assert.Equal(t, ServerDrive{BootOrder: 3, Device: "ide", DevChannel: "0:1", Drive: &Drive{UUID: "seed-uuid"}}, server.Drives[2])
+}
+
+func TestAttachDrive_emptyDrive(t *testing.T) {
+	err := AttachDrive(&Server{}, &Drive{}, "ide")
+
+	assert.Error(t, err)
+	assert.Equal(t, ErrEmptyArgument.Error(), err.Error())
+}
+
+func TestServerStatus(t *testing.T) {
+	assert.True(t, ServerStatusRunning.IsValid())
+	assert.False(t, ServerStatus("runing").IsValid())
+	assert.True(t, ServerStatusStopping.IsTransitional())
+	assert.False(t, ServerStatusStopped.IsTransitional())
+	assert.True(t, NICModelVirtio.IsValid())
+	assert.False(t, NICModel("ne2000").IsValid())
+	assert.True(t, IPConfigurationManual.IsValid())
+	assert.False(t, IPConfigurationType("auto").IsValid())
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"time"
 )
 
 const snapshotsBasePath = "snapshots"
@@ -50,11 +51,26 @@ type SnapshotUpdateRequest struct {
 	*Snapshot
 }
 
+// SnapshotCloneRequest represents a request to clone a snapshot to a new
+// drive. All fields are optional and override the values of the snapshot
+// drive. Size of the new drive can only be bigger or the same.
+type SnapshotCloneRequest struct {
+	Media       DriveMedia  `json:"media,omitempty"`
+	Name        string      `json:"name,omitempty"`
+	Size        int         `json:"size,omitempty"`
+	StorageType StorageType `json:"storage_type,omitempty"`
+}
+
 type snapshotsRoot struct {
 	Meta      *Meta      `json:"meta,omitempty"`
 	Snapshots []Snapshot `json:"objects"`
 }
 
+// ParsedTimestamp parses the timestamp of the snapshot creation.
+func (s Snapshot) ParsedTimestamp() (time.Time, error) {
+	return parseTimestamp(s.Timestamp)
+}
+
 func (s Snapshot) String() string {
 	return Stringify(s)
 }
@@ -178,3 +194,35 @@ func (s *SnapshotsService) Delete(ctx context.Context, uuid string) (*Response,
 
 	return s.client.Do(ctx, req, nil)
 }
+
+// Clone creates a new drive from a snapshot identified by uuid.
+// SnapshotCloneRequest is optional.
+//
+// CloudSigma API docs: https://cloudsigma-docs.readthedocs.io/en/latest/snapshots.html#cloning-snapshot-to-drive
+func (s *SnapshotsService) Clone(ctx context.Context, uuid string, cloneRequest *SnapshotCloneRequest) (*Drive, *Response, error) {
+	if cloneRequest == nil {
+		cloneRequest = new(SnapshotCloneRequest)
+	}
+	return s.doAction(ctx, uuid, "clone", cloneRequest)
+}
+
+func (s *SnapshotsService) doAction(ctx context.Context, uuid, action string, body interface{}) (*Drive, *Response, error) {
+	if uuid == "" || action == "" {
+		return nil, nil, ErrEmptyArgument
+	}
+
+	path := fmt.Sprintf("%v/%v/action/?do=%v", snapshotsBasePath, uuid, action)
+
+	req, err := s.client.NewRequest(http.MethodPost, path, body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	drive := new(Drive)
+	resp, err := s.client.Do(ctx, req, drive)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return drive, resp, nil
+}
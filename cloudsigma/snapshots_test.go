@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -175,3 +176,71 @@ func TestSnapshots_Delete_emptyUUID(t *testing.T) {
 	assert.Error(t, err)
 	assert.Equal(t, ErrEmptyArgument.Error(), err.Error())
 }
+
+func TestSnapshots_Clone(t *testing.T) {
+	setup()
+	defer teardown()
+
+	input := &SnapshotCloneRequest{
+		Media:       "disk",
+		Name:        "restored drive",
+		Size:        3221225472, // 3GB
+		StorageType: "dssd",
+	}
+	mux.HandleFunc("/snapshots/long-uuid/action/", func(w http.ResponseWriter, r *http.Request) {
+		v := new(SnapshotCloneRequest)
+		_ = json.NewDecoder(r.Body).Decode(v)
+		assert.Equal(t, input, v)
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "clone", r.URL.Query().Get("do"))
+		_, _ = fmt.Fprint(w, `{"media":"disk","name":"restored drive","size":3221225472,"status":"cloning_dst","storage_type":"dssd","uuid":"drive-uuid"}`)
+	})
+	expected := &Drive{
+		Media:       "disk",
+		Name:        "restored drive",
+		Size:        3221225472,
+		Status:      "cloning_dst",
+		StorageType: "dssd",
+		UUID:        "drive-uuid",
+	}
+
+	drive, _, err := client.Snapshots.Clone(ctx, "long-uuid", input)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, drive)
+}
+
+func TestSnapshots_Clone_emptyPayload(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/snapshots/long-uuid/action/", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "clone", r.URL.Query().Get("do"))
+		_, _ = fmt.Fprint(w, `{"uuid":"drive-uuid"}`)
+	})
+	expected := &Drive{
+		UUID: "drive-uuid",
+	}
+
+	drive, _, err := client.Snapshots.Clone(ctx, "long-uuid", nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, drive)
+}
+
+func TestSnapshots_Clone_emptyUUID(t *testing.T) {
+	_, _, err := client.Snapshots.Clone(ctx, "", nil)
+
+	assert.Error(t, err)
+	assert.Equal(t, ErrEmptyArgument.Error(), err.Error())
+}
+
+func TestSnapshots_ParsedTimestamp(t *testing.T) {
+	snapshot := Snapshot{Timestamp: "2024-05-01T10:20:30+00:00"}
+
+	timestamp, err := snapshot.ParsedTimestamp()
+
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 5, 1, 10, 20, 30, 0, time.UTC), timestamp.UTC())
+}
@@ -68,6 +68,10 @@ func stringifyStruct(w io.Writer, v reflect.Value) {
 		if fv.Kind() == reflect.Slice && fv.IsNil() {
 			continue
 		}
+		// fields not sent to the API, like Extra, are only shown when set
+		if fv.Kind() == reflect.Map && fv.IsNil() && v.Type().Field(i).Tag.Get("json") == "-" {
+			continue
+		}
 
 		if sep {
 			_, _ = w.Write([]byte(", "))
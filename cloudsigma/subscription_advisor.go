@@ -0,0 +1,412 @@
+package cloudsigma
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Defaults of SubscriptionAdvisorOptions.
+var (
+	defaultAdvisorResources = []string{resourceCPU, resourceMemory, resourceDSSD}
+	defaultAdvisorPeriods   = []string{"1 month", "1 year"}
+	defaultAdvisorLookback  = 30 * 24 * time.Hour
+
+	// advisorGranularity is the unit recommended amounts are rounded up to.
+	advisorGranularity = map[string]int64{
+		resourceCPU:      1000,
+		resourceMemory:   gibibyte,
+		resourceDSSD:     gibibyte,
+		resourceMagnetic: gibibyte,
+	}
+)
+
+const gibibyte = 1024 * 1024 * 1024
+
+// SubscriptionAdvisorOptions specifies the optional parameters of a
+// SubscriptionAdvisor.
+type SubscriptionAdvisorOptions struct {
+	// Resources to advise on. Defaults to cpu, mem and dssd.
+	Resources []string
+	// Periods to compare, e.g. "1 month" or "1 year". Defaults to 1 month
+	// and 1 year.
+	Periods []string
+	// Lookback is how far back burst usage is observed. Defaults to 30 days.
+	Lookback time.Duration
+	// Now is the time of the advice. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// SubscriptionAdvisor recommends subscriptions which minimize the expected
+// cost of the observed usage.
+type SubscriptionAdvisor struct {
+	client *Client
+	opts   SubscriptionAdvisorOptions
+}
+
+// SubscriptionRecommendation represents the advice for a single resource.
+// Amount is the additional amount to subscribe in base units, and zero if
+// paying burst rates is cheaper. Costs are expected monthly (30 days) costs
+// of the burst part of the usage.
+type SubscriptionRecommendation struct {
+	Resource string
+	Amount   int64
+	Period   string
+	// Price of the recommended subscription for the whole period.
+	Price Decimal
+	// BurstCost is the expected monthly cost without the subscription.
+	BurstCost Decimal
+	// Cost is the expected monthly cost with the subscription, including
+	// the remaining burst.
+	Cost Decimal
+	// Explanation describes how the recommendation was computed.
+	Explanation string
+}
+
+// Savings returns the expected monthly savings of the recommendation.
+func (r SubscriptionRecommendation) Savings() Decimal {
+	return r.BurstCost.Sub(r.Cost)
+}
+
+// SubscriptionAdvice represents recommendations of a SubscriptionAdvisor.
+type SubscriptionAdvice struct {
+	Currency        string
+	Recommendations []SubscriptionRecommendation
+}
+
+// CreateRequest returns a request creating the recommended subscriptions.
+// Recommendations with zero amount are skipped.
+func (a *SubscriptionAdvice) CreateRequest() *SubscriptionCreateRequest {
+	createRequest := &SubscriptionCreateRequest{}
+	for _, r := range a.Recommendations {
+		if r.Amount <= 0 {
+			continue
+		}
+		createRequest.Subscriptions = append(createRequest.Subscriptions, Subscription{
+			Amount:   strconv.FormatInt(r.Amount, 10),
+			Period:   r.Period,
+			Resource: r.Resource,
+		})
+	}
+	return createRequest
+}
+
+// NewSubscriptionAdvisor returns a new SubscriptionAdvisor.
+func NewSubscriptionAdvisor(client *Client, opts *SubscriptionAdvisorOptions) *SubscriptionAdvisor {
+	a := &SubscriptionAdvisor{client: client}
+	if opts != nil {
+		a.opts = *opts
+	}
+	if len(a.opts.Resources) == 0 {
+		a.opts.Resources = defaultAdvisorResources
+	}
+	if len(a.opts.Periods) == 0 {
+		a.opts.Periods = defaultAdvisorPeriods
+	}
+	if a.opts.Lookback == 0 {
+		a.opts.Lookback = defaultAdvisorLookback
+	}
+	if a.opts.Now == nil {
+		a.opts.Now = time.Now
+	}
+	return a
+}
+
+// Advise observes burst usage and existing subscriptions, and recommends a
+// subscription amount and period per resource.
+//
+// Every burst usage record in the lookback window is a sample of the amount
+// used over the subscriptions, increased by subscriptions ending within a
+// month without automatic renewal. Subscribing one more unit costs the
+// subscription price s, and saves the burst price b whenever the usage
+// exceeds the amount. The expected cost is minimal for the smallest amount
+// exceeded by at most s/b of the samples. The amount is rounded up to whole
+// GHz or GB, priced for every period with the subscription calculator, and
+// recommended with the cheapest period if it lowers the expected cost.
+func (a *SubscriptionAdvisor) Advise(ctx context.Context) (*SubscriptionAdvice, error) {
+	if a.client == nil {
+		return nil, ErrEmptyArgument
+	}
+
+	profile, _, err := a.client.Profile.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pricing, _, err := a.client.Pricing.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	samples, err := a.burstSamples(ctx)
+	if err != nil {
+		return nil, err
+	}
+	expiring, err := a.expiringAmounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	advice := &SubscriptionAdvice{Currency: profile.Currency}
+	// adjusted holds the samples with the expiring amounts added
+	adjusted := make(map[string][]int64, len(a.opts.Resources))
+	for _, resource := range a.opts.Resources {
+		burst, ok := pricing.BurstPrice(resource, profile.Currency)
+		if !ok {
+			return nil, fmt.Errorf("%w: no burst price of %q in %v", ErrResourceNotFound, resource, profile.Currency)
+		}
+		subscription, ok := pricing.CurrentPrice(resource, profile.Currency)
+		if !ok {
+			return nil, fmt.Errorf("%w: no subscription price of %q in %v", ErrResourceNotFound, resource, profile.Currency)
+		}
+
+		resourceSamples := make([]int64, len(samples[resource]))
+		for i, sample := range samples[resource] {
+			resourceSamples[i] = sample + expiring[resource]
+		}
+		adjusted[resource] = resourceSamples
+		ratio := priceRatio(subscription, burst)
+		amount := optimalAmount(resourceSamples, ratio)
+		if granularity := advisorGranularity[resource]; granularity > 0 && amount%granularity != 0 {
+			amount += granularity - amount%granularity
+		}
+
+		r := SubscriptionRecommendation{
+			Resource:  resource,
+			Amount:    amount,
+			BurstCost: expectedBurstCost(burst, resourceSamples, 0),
+		}
+		r.Cost = r.BurstCost
+		r.Explanation = fmt.Sprintf("%v: %d burst samples since %v, %d units expiring without renewal; "+
+			"subscription costs %v%% of burst, so subscribing pays off for amounts exceeded by more than %v%% of samples",
+			resource, len(resourceSamples), a.opts.Now().Add(-a.opts.Lookback).Format(time.RFC3339), expiring[resource],
+			ratio.Mul(NewDecimal(100, 0)).Round(2), ratio.Mul(NewDecimal(100, 0)).Round(2))
+		advice.Recommendations = append(advice.Recommendations, r)
+	}
+
+	candidates := make(map[string]*SubscriptionRecommendation)
+	for i, r := range advice.Recommendations {
+		if r.Amount > 0 {
+			candidates[r.Resource] = &advice.Recommendations[i]
+		}
+	}
+	if len(candidates) > 0 {
+		if err := a.pricePeriods(ctx, pricing, profile.Currency, adjusted, candidates); err != nil {
+			return nil, err
+		}
+	}
+
+	return advice, nil
+}
+
+// Apply creates the recommended subscriptions confirmed by confirm. As
+// subscriptions are charged, confirm is required; pass a function returning
+// true to create all recommended subscriptions.
+func (a *SubscriptionAdvisor) Apply(ctx context.Context, advice *SubscriptionAdvice, confirm func(SubscriptionRecommendation) bool) ([]Subscription, error) {
+	if advice == nil || confirm == nil {
+		return nil, ErrEmptyArgument
+	}
+
+	confirmed := &SubscriptionAdvice{Currency: advice.Currency}
+	for _, r := range advice.Recommendations {
+		if r.Amount > 0 && confirm(r) {
+			confirmed.Recommendations = append(confirmed.Recommendations, r)
+		}
+	}
+	if len(confirmed.Recommendations) == 0 {
+		return nil, nil
+	}
+
+	subscriptions, _, err := a.client.Subscriptions.Create(ctx, confirmed.CreateRequest())
+	return subscriptions, err
+}
+
+// burstSamples returns the observed burst amounts per resource. Resources
+// without burst usage records are sampled from the current usage.
+func (a *SubscriptionAdvisor) burstSamples(ctx context.Context) (map[string][]int64, error) {
+	opts := &UsageListOptions{
+		Since:     a.opts.Now().Add(-a.opts.Lookback),
+		Resources: a.opts.Resources,
+	}
+	records, _, err := a.client.BurstUsage.List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	samples := make(map[string][]int64)
+	for _, r := range records {
+		samples[r.Resource] = append(samples[r.Resource], r.Amount.Int64())
+	}
+
+	var currentUsage *CurrentUsage
+	for _, resource := range a.opts.Resources {
+		if len(samples[resource]) > 0 {
+			continue
+		}
+		if currentUsage == nil {
+			currentUsage, _, err = a.client.CurrentUsage.Get(ctx)
+			if err != nil {
+				return nil, err
+			}
+		}
+		samples[resource] = []int64{currentUsage.Usage[resource].Burst}
+	}
+	return samples, nil
+}
+
+// expiringAmounts returns amounts of active subscriptions per resource which
+// end within a month and are not renewed automatically.
+func (a *SubscriptionAdvisor) expiringAmounts(ctx context.Context) (map[string]int64, error) {
+	subscriptions, _, err := a.client.Subscriptions.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := a.opts.Now()
+	amounts := make(map[string]int64)
+	for _, s := range subscriptions {
+		if s.AutoRenew || (s.Status != "" && s.Status != "active") {
+			continue
+		}
+		left, err := s.TimeLeft(now)
+		if err != nil || left > secondsPerMonth*time.Second {
+			continue
+		}
+		amount, err := s.ParsedAmount()
+		if err != nil {
+			continue
+		}
+		amounts[s.Resource] += amount.Int64()
+	}
+	return amounts, nil
+}
+
+// pricePeriods prices the candidate amounts for every period with the
+// subscription calculator, and picks the period with the lowest expected
+// monthly cost. samples include the expiring amounts. Candidates not lowering
+// the expected cost get zero amount.
+func (a *SubscriptionAdvisor) pricePeriods(ctx context.Context, pricing *Pricing, currency string, samples map[string][]int64, candidates map[string]*SubscriptionRecommendation) error {
+	resources := make([]string, 0, len(candidates))
+	for resource := range candidates {
+		resources = append(resources, resource)
+	}
+	sort.Strings(resources)
+
+	for _, period := range a.opts.Periods {
+		months, err := periodMonths(period)
+		if err != nil {
+			return err
+		}
+
+		calculatorRequest := &SubscriptionCalculatorRequest{}
+		for _, resource := range resources {
+			calculatorRequest.Subscriptions = append(calculatorRequest.Subscriptions, Subscription{
+				Amount:   strconv.FormatInt(candidates[resource].Amount, 10),
+				Period:   period,
+				Resource: resource,
+			})
+		}
+		calculation, _, err := a.client.Subscriptions.Calculate(ctx, calculatorRequest)
+		if err != nil {
+			return err
+		}
+
+		for _, s := range calculation.Subscriptions {
+			r, ok := candidates[s.Resource]
+			if !ok {
+				continue
+			}
+			price, err := s.ParsedPrice()
+			if err != nil {
+				return err
+			}
+			burst, _ := pricing.BurstPrice(s.Resource, currency)
+			cost := price.Div(months, 10).Add(expectedBurstCost(burst, samples[s.Resource], r.Amount))
+			if r.Period == "" || cost.Cmp(r.Cost) < 0 {
+				r.Period = period
+				r.Price = price
+				r.Cost = cost
+			}
+		}
+	}
+
+	for _, resource := range resources {
+		r := candidates[resource]
+		if r.Period == "" || r.Cost.Cmp(r.BurstCost) >= 0 {
+			r.Explanation += fmt.Sprintf("; %d units priced by the calculator do not lower the expected monthly cost of %v %v",
+				r.Amount, r.BurstCost.Round(2), currency)
+			r.Amount, r.Period, r.Price, r.Cost = 0, "", Decimal{}, r.BurstCost
+			continue
+		}
+		r.Explanation += fmt.Sprintf("; recommended %d units for %v at %v %v, lowering the expected monthly cost from %v to %v %v",
+			r.Amount, r.Period, r.Price.Round(2), currency, r.BurstCost.Round(2), r.Cost.Round(2), currency)
+	}
+	return nil
+}
+
+// priceRatio returns the price of a base unit of subscription relative to
+// the burst price.
+func priceRatio(subscription, burst *Price) Decimal {
+	if burst.Price.IsZero() || subscription.Multiplier == 0 {
+		return NewDecimal(1, 0)
+	}
+	return subscription.Price.Mul(NewDecimal(burst.Multiplier, 0)).
+		Div(burst.Price.Mul(NewDecimal(subscription.Multiplier, 0)), 10)
+}
+
+// optimalAmount returns the smallest amount exceeded by at most the ratio
+// of samples.
+func optimalAmount(samples []int64, ratio Decimal) int64 {
+	if len(samples) == 0 || ratio.Cmp(NewDecimal(1, 0)) >= 0 {
+		return 0
+	}
+	sorted := append([]int64(nil), samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	n := NewDecimal(int64(len(sorted)), 0)
+	candidates := append([]int64{0}, sorted...)
+	for _, amount := range candidates {
+		exceeding := len(sorted) - sort.Search(len(sorted), func(i int) bool { return sorted[i] > amount })
+		if NewDecimal(int64(exceeding), 0).Div(n, 10).Cmp(ratio) <= 0 {
+			return amount
+		}
+	}
+	return sorted[len(sorted)-1]
+}
+
+// expectedBurstCost returns the mean monthly burst cost of the samples over
+// the subscribed amount.
+func expectedBurstCost(burst *Price, samples []int64, amount int64) Decimal {
+	if len(samples) == 0 {
+		return Decimal{}
+	}
+	var total Decimal
+	for _, sample := range samples {
+		total = total.Add(burst.Cost(max(0, sample-amount), secondsPerMonth))
+	}
+	return total.Div(NewDecimal(int64(len(samples)), 0), 10)
+}
+
+// periodMonths parses a subscription period like "1 month", "30 days" or
+// "1 year" into months of 30 days.
+func periodMonths(period string) (Decimal, error) {
+	fields := strings.Fields(period)
+	if len(fields) != 2 {
+		return Decimal{}, fmt.Errorf("cloudsigma-sdk-go: cannot parse period %q", period)
+	}
+	n, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil || n <= 0 {
+		return Decimal{}, fmt.Errorf("cloudsigma-sdk-go: cannot parse period %q", period)
+	}
+
+	switch strings.TrimSuffix(strings.ToLower(fields[1]), "s") {
+	case "day":
+		return NewDecimal(n, 0).Div(NewDecimal(30, 0), 10), nil
+	case "month":
+		return NewDecimal(n, 0), nil
+	case "year":
+		return NewDecimal(12*n, 0), nil
+	}
+	return Decimal{}, fmt.Errorf("cloudsigma-sdk-go: cannot parse period %q", period)
+}
@@ -0,0 +1,163 @@
+package cloudsigma
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var advisorNow = time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+
+func setupSubscriptionAdvisor(t *testing.T, created *SubscriptionCreateRequest) *SubscriptionAdvisor {
+	setup()
+
+	mux.HandleFunc("/profile/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"currency":"USD","uuid":"user-uuid"}`)
+	})
+	mux.HandleFunc("/pricing/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, pricingJSON)
+	})
+	mux.HandleFunc("/burstusage/", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "2024-06-01T00:00:00Z", r.URL.Query().Get("time__gte"))
+		assert.Equal(t, "cpu,mem", r.URL.Query().Get("resource"))
+		_, _ = fmt.Fprint(w, `{"objects":[
+			{"amount":"0","resource":"cpu"},
+			{"amount":"1000","resource":"cpu"},
+			{"amount":"2000","resource":"cpu"},
+			{"amount":"3000","resource":"cpu"}
+		]}`)
+	})
+	mux.HandleFunc("/currentusage/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"usage":{"mem":{"burst":0,"subscribed":1073741824,"using":1073741824}}}`)
+	})
+	mux.HandleFunc("/subscriptions/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			_ = json.NewDecoder(r.Body).Decode(created)
+			_, _ = fmt.Fprint(w, `{"objects":[{"amount":"1000","period":"1 year","resource":"cpu","uuid":"new-uuid"}]}`)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"objects":[
+			{"amount":"1073741824","auto_renew":false,"end_time":"2024-07-10T00:00:00+00:00","resource":"mem","status":"active"},
+			{"amount":"5000","auto_renew":true,"end_time":"2024-07-10T00:00:00+00:00","resource":"cpu","status":"active"}
+		]}`)
+	})
+	prices := map[string]string{
+		"cpu/1 month": "10",
+		"cpu/1 year":  "96",
+		"mem/1 month": "8",
+		"mem/1 year":  "100",
+	}
+	mux.HandleFunc("/subscriptioncalculator/", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		v := new(SubscriptionCalculatorRequest)
+		_ = json.NewDecoder(r.Body).Decode(v)
+		for i, s := range v.Subscriptions {
+			v.Subscriptions[i].Price = prices[s.Resource+"/"+s.Period]
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"objects": v.Subscriptions})
+	})
+
+	return NewSubscriptionAdvisor(client, &SubscriptionAdvisorOptions{
+		Resources: []string{"cpu", "mem"},
+		Now:       func() time.Time { return advisorNow },
+	})
+}
+
+func TestSubscriptionAdvisor_Advise(t *testing.T) {
+	advisor := setupSubscriptionAdvisor(t, nil)
+	defer teardown()
+
+	advice, err := advisor.Advise(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "USD", advice.Currency)
+	assert.Len(t, advice.Recommendations, 2)
+
+	// subscription costs 50% of burst, 1000 MHz are exceeded by 2 of 4 samples
+	cpu := advice.Recommendations[0]
+	assert.Equal(t, "cpu", cpu.Resource)
+	assert.Equal(t, int64(1000), cpu.Amount)
+	assert.Equal(t, "1 year", cpu.Period)
+	assertDecimal(t, "96", cpu.Price)
+	// burst of (0 + 1 + 2 + 3) GHz / 4 * 20
+	assertDecimal(t, "30", cpu.BurstCost)
+	// 96 / 12 + burst of (0 + 0 + 1 + 2) GHz / 4 * 20
+	assertDecimal(t, "23", cpu.Cost)
+	assertDecimal(t, "7", cpu.Savings())
+	assert.Contains(t, cpu.Explanation, "subscription costs 50.00% of burst")
+	assert.Contains(t, cpu.Explanation, "recommended 1000 units for 1 year at 96.00 USD")
+
+	// 1 GB expiring without renewal is going to burst
+	mem := advice.Recommendations[1]
+	assert.Equal(t, int64(gibibyte), mem.Amount)
+	assert.Equal(t, "1 month", mem.Period)
+	assertDecimal(t, "15", mem.BurstCost)
+	assertDecimal(t, "8", mem.Cost)
+	assert.Contains(t, mem.Explanation, "1073741824 units expiring without renewal")
+}
+
+func TestSubscriptionAdvisor_Apply(t *testing.T) {
+	created := new(SubscriptionCreateRequest)
+	advisor := setupSubscriptionAdvisor(t, created)
+	defer teardown()
+
+	advice, _ := advisor.Advise(ctx)
+	subscriptions, err := advisor.Apply(ctx, advice, func(r SubscriptionRecommendation) bool {
+		return r.Resource == "cpu"
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Subscription{{Amount: "1000", Period: "1 year", Resource: "cpu"}}, created.Subscriptions)
+	assert.Equal(t, "new-uuid", subscriptions[0].UUID)
+}
+
+func TestSubscriptionAdvisor_Apply_noneConfirmed(t *testing.T) {
+	advisor := NewSubscriptionAdvisor(client, nil)
+	advice := &SubscriptionAdvice{Recommendations: []SubscriptionRecommendation{{Resource: "cpu", Amount: 1000}}}
+
+	subscriptions, err := advisor.Apply(ctx, advice, func(SubscriptionRecommendation) bool { return false })
+
+	assert.NoError(t, err)
+	assert.Nil(t, subscriptions)
+}
+
+func TestSubscriptionAdvisor_Apply_nilConfirm(t *testing.T) {
+	advisor := NewSubscriptionAdvisor(client, nil)
+	advice := &SubscriptionAdvice{Recommendations: []SubscriptionRecommendation{{Resource: "cpu", Amount: 1000}}}
+
+	_, err := advisor.Apply(ctx, advice, nil)
+
+	assert.ErrorIs(t, err, ErrEmptyArgument)
+}
+
+func TestOptimalAmount(t *testing.T) {
+	samples := []int64{400, 100, 300, 200}
+
+	assert.Equal(t, int64(0), optimalAmount(samples, MustParseDecimal("1")))
+	assert.Equal(t, int64(400), optimalAmount(samples, MustParseDecimal("0.1")))
+	assert.Equal(t, int64(300), optimalAmount(samples, MustParseDecimal("0.25")))
+	assert.Equal(t, int64(100), optimalAmount(samples, MustParseDecimal("0.8")))
+	assert.Equal(t, int64(0), optimalAmount(nil, MustParseDecimal("0.5")))
+}
+
+func TestPeriodMonths(t *testing.T) {
+	tests := map[string]string{
+		"1 month":  "1",
+		"3 months": "3",
+		"1 year":   "12",
+		"15 days":  "0.5",
+	}
+	for period, expected := range tests {
+		months, err := periodMonths(period)
+
+		assert.NoError(t, err, period)
+		assertDecimal(t, expected, months)
+	}
+
+	_, err := periodMonths("forever")
+	assert.Error(t, err)
+}
@@ -4,9 +4,13 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"time"
 )
 
-const subscriptionsBasePath = "subscriptions"
+const (
+	subscriptionsBasePath          = "subscriptions"
+	subscriptionCalculatorBasePath = "subscriptioncalculator"
+)
 
 // SubscriptionsService handles communication with the subscriptions related
 // methods of the CloudSigma API.
@@ -37,6 +41,33 @@ type SubscriptionCreateRequest struct {
 	Subscriptions []Subscription `json:"objects"`
 }
 
+// SubscriptionUpdateRequest represents a request to update a subscription.
+// Only the automatic renewal of a subscription can be changed.
+type SubscriptionUpdateRequest struct {
+	AutoRenew bool `json:"auto_renew"`
+}
+
+// SubscriptionExtendRequest represents a request to extend a subscription
+// either by a period, e.g. "1 month", or until an end time.
+type SubscriptionExtendRequest struct {
+	EndTime string `json:"end_time,omitempty"`
+	Period  string `json:"period,omitempty"`
+}
+
+// SubscriptionCalculatorRequest represents a request to calculate the price
+// of proposed subscriptions. Amount, Period and Resource of the
+// subscriptions are used.
+type SubscriptionCalculatorRequest struct {
+	Subscriptions []Subscription `json:"objects"`
+}
+
+// SubscriptionCalculation represents the price of proposed subscriptions.
+// Subscriptions contain the price of every single subscription.
+type SubscriptionCalculation struct {
+	Price         Decimal        `json:"price"`
+	Subscriptions []Subscription `json:"objects,omitempty"`
+}
+
 type subscriptionsRoot struct {
 	Meta          *Meta          `json:"meta,omitempty"`
 	Subscriptions []Subscription `json:"objects,omitempty"`
@@ -88,3 +119,144 @@ func (s *SubscriptionsService) Create(ctx context.Context, createRequest *Subscr
 
 	return root.Subscriptions, resp, nil
 }
+
+// Get provides detailed information for a subscription identified by id.
+//
+// CloudSigma API docs: https://cloudsigma-docs.readthedocs.io/en/latest/subscriptions.html#listing
+func (s *SubscriptionsService) Get(ctx context.Context, id string) (*Subscription, *Response, error) {
+	if id == "" {
+		return nil, nil, ErrEmptyArgument
+	}
+
+	path := fmt.Sprintf("%v/%v/", subscriptionsBasePath, id)
+
+	req, err := s.client.NewRequest(http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	subscription := new(Subscription)
+	resp, err := s.client.Do(ctx, req, subscription)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return subscription, resp, nil
+}
+
+// Update edits a subscription identified by id, e.g. to turn off its
+// automatic renewal.
+//
+// CloudSigma API docs: https://cloudsigma-docs.readthedocs.io/en/latest/subscriptions.html#editing
+func (s *SubscriptionsService) Update(ctx context.Context, id string, updateRequest *SubscriptionUpdateRequest) (*Subscription, *Response, error) {
+	if id == "" {
+		return nil, nil, ErrEmptyArgument
+	}
+	if updateRequest == nil {
+		return nil, nil, ErrEmptyPayloadNotAllowed
+	}
+
+	path := fmt.Sprintf("%v/%v/", subscriptionsBasePath, id)
+
+	req, err := s.client.NewRequest(http.MethodPut, path, updateRequest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	subscription := new(Subscription)
+	resp, err := s.client.Do(ctx, req, subscription)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return subscription, resp, nil
+}
+
+// Extend prolongs a subscription identified by id. Without period and end
+// time the subscription is renewed for its original period.
+//
+// CloudSigma API docs: https://cloudsigma-docs.readthedocs.io/en/latest/subscriptions.html#extending
+func (s *SubscriptionsService) Extend(ctx context.Context, id string, extendRequest *SubscriptionExtendRequest) (*Subscription, *Response, error) {
+	if id == "" {
+		return nil, nil, ErrEmptyArgument
+	}
+	if extendRequest == nil {
+		extendRequest = new(SubscriptionExtendRequest)
+	}
+
+	path := fmt.Sprintf("%v/%v/action/?do=extend", subscriptionsBasePath, id)
+
+	req, err := s.client.NewRequest(http.MethodPost, path, extendRequest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	subscription := new(Subscription)
+	resp, err := s.client.Do(ctx, req, subscription)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return subscription, resp, nil
+}
+
+// Calculate provides the price of proposed subscriptions without creating
+// them.
+//
+// CloudSigma API docs: https://cloudsigma-docs.readthedocs.io/en/latest/subscriptions.html#subscription-calculator
+func (s *SubscriptionsService) Calculate(ctx context.Context, calculatorRequest *SubscriptionCalculatorRequest) (*SubscriptionCalculation, *Response, error) {
+	if calculatorRequest == nil {
+		return nil, nil, ErrEmptyPayloadNotAllowed
+	}
+
+	path := fmt.Sprintf("%v/", subscriptionCalculatorBasePath)
+
+	req, err := s.client.NewRequest(http.MethodPost, path, calculatorRequest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	calculation := new(SubscriptionCalculation)
+	resp, err := s.client.Do(ctx, req, calculation)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return calculation, resp, nil
+}
+
+// ParsedStartTime parses the start time of the subscription.
+func (s Subscription) ParsedStartTime() (time.Time, error) {
+	return parseTimestamp(s.StartTime)
+}
+
+// ParsedEndTime parses the end time of the subscription.
+func (s Subscription) ParsedEndTime() (time.Time, error) {
+	return parseTimestamp(s.EndTime)
+}
+
+// ParsedAmount parses the subscribed amount in base units of the resource,
+// e.g. MHz for CPU or bytes for memory.
+func (s Subscription) ParsedAmount() (Decimal, error) {
+	return ParseDecimal(s.Amount)
+}
+
+// ParsedRemaining parses the amount of the subscription which is not used
+// by resources yet.
+func (s Subscription) ParsedRemaining() (Decimal, error) {
+	return ParseDecimal(s.Remaining)
+}
+
+// ParsedPrice parses the price paid for the subscription.
+func (s Subscription) ParsedPrice() (Decimal, error) {
+	return ParseDecimal(s.Price)
+}
+
+// TimeLeft returns the time left until the subscription ends.
+func (s Subscription) TimeLeft(now time.Time) (time.Duration, error) {
+	end, err := s.ParsedEndTime()
+	if err != nil {
+		return 0, err
+	}
+	return end.Sub(now), nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -63,3 +64,144 @@ func TestSubscriptions_Create_emptyPayload(t *testing.T) {
 	assert.Error(t, err)
 	assert.Equal(t, ErrEmptyPayloadNotAllowed.Error(), err.Error())
 }
+
+func TestSubscriptions_Get(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/subscriptions/42/", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		_, _ = fmt.Fprint(w, `{"amount":"3000","id":"42","resource":"dssd","uuid":"long-uuid"}`)
+	})
+	expected := &Subscription{Amount: "3000", ID: "42", Resource: "dssd", UUID: "long-uuid"}
+
+	subscription, _, err := client.Subscriptions.Get(ctx, "42")
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, subscription)
+}
+
+func TestSubscriptions_Get_emptyID(t *testing.T) {
+	_, _, err := client.Subscriptions.Get(ctx, "")
+
+	assert.Error(t, err)
+	assert.Equal(t, ErrEmptyArgument.Error(), err.Error())
+}
+
+func TestSubscriptions_Update(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/subscriptions/42/", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		v := make(map[string]interface{})
+		_ = json.NewDecoder(r.Body).Decode(&v)
+		assert.Equal(t, map[string]interface{}{"auto_renew": false}, v)
+		_, _ = fmt.Fprint(w, `{"auto_renew":false,"id":"42"}`)
+	})
+	expected := &Subscription{ID: "42"}
+
+	subscription, _, err := client.Subscriptions.Update(ctx, "42", &SubscriptionUpdateRequest{AutoRenew: false})
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, subscription)
+}
+
+func TestSubscriptions_Update_emptyPayload(t *testing.T) {
+	_, _, err := client.Subscriptions.Update(ctx, "42", nil)
+
+	assert.Error(t, err)
+	assert.Equal(t, ErrEmptyPayloadNotAllowed.Error(), err.Error())
+}
+
+func TestSubscriptions_Extend(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/subscriptions/42/action/", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "extend", r.URL.Query().Get("do"))
+		v := new(SubscriptionExtendRequest)
+		_ = json.NewDecoder(r.Body).Decode(v)
+		assert.Equal(t, &SubscriptionExtendRequest{Period: "1 month"}, v)
+		_, _ = fmt.Fprint(w, `{"end_time":"2024-08-01T00:00:00+00:00","id":"42"}`)
+	})
+	expected := &Subscription{EndTime: "2024-08-01T00:00:00+00:00", ID: "42"}
+
+	subscription, _, err := client.Subscriptions.Extend(ctx, "42", &SubscriptionExtendRequest{Period: "1 month"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, subscription)
+}
+
+func TestSubscriptions_Extend_emptyID(t *testing.T) {
+	_, _, err := client.Subscriptions.Extend(ctx, "", nil)
+
+	assert.Error(t, err)
+	assert.Equal(t, ErrEmptyArgument.Error(), err.Error())
+}
+
+func TestSubscriptions_Calculate(t *testing.T) {
+	setup()
+	defer teardown()
+
+	input := &SubscriptionCalculatorRequest{
+		Subscriptions: []Subscription{{Amount: "2000", Period: "1 month", Resource: "cpu"}},
+	}
+	mux.HandleFunc("/subscriptioncalculator/", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		v := new(SubscriptionCalculatorRequest)
+		_ = json.NewDecoder(r.Body).Decode(v)
+		assert.Equal(t, input, v)
+		_, _ = fmt.Fprint(w, `{"objects":[{"amount":"2000","period":"1 month","price":"20.00","resource":"cpu"}],"price":"20.00"}`)
+	})
+	expected := &SubscriptionCalculation{
+		Price:         MustParseDecimal("20.00"),
+		Subscriptions: []Subscription{{Amount: "2000", Period: "1 month", Price: "20.00", Resource: "cpu"}},
+	}
+
+	calculation, _, err := client.Subscriptions.Calculate(ctx, input)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, calculation)
+}
+
+func TestSubscriptions_Calculate_emptyPayload(t *testing.T) {
+	_, _, err := client.Subscriptions.Calculate(ctx, nil)
+
+	assert.Error(t, err)
+	assert.Equal(t, ErrEmptyPayloadNotAllowed.Error(), err.Error())
+}
+
+func TestSubscription_parsed(t *testing.T) {
+	subscription := Subscription{
+		Amount:    "10737418240",
+		EndTime:   "2024-08-01T00:00:00+00:00",
+		Price:     "12.50",
+		Remaining: "5368709120",
+		StartTime: "2024-07-01 00:00:00",
+	}
+
+	start, err := subscription.ParsedStartTime()
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC), start)
+
+	left, err := subscription.TimeLeft(time.Date(2024, 7, 31, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, 24*time.Hour, left)
+
+	amount, err := subscription.ParsedAmount()
+	assert.NoError(t, err)
+	assert.Equal(t, "10737418240", amount.String())
+
+	remaining, err := subscription.ParsedRemaining()
+	assert.NoError(t, err)
+	assert.Equal(t, "5368709120", remaining.String())
+
+	price, err := subscription.ParsedPrice()
+	assert.NoError(t, err)
+	assert.Equal(t, "12.50", price.String())
+
+	_, err = Subscription{}.ParsedEndTime()
+	assert.Error(t, err)
+}
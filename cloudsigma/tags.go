@@ -2,12 +2,17 @@ package cloudsigma
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 )
 
 const tagsBasePath = "tags"
 
+// tagUpdateAttempts is the number of attempts to change the resources of a
+// tag when concurrent updates conflict.
+const tagUpdateAttempts = 5
+
 // TagsService handles communication with the tags related methods of
 // the CloudSigma API.
 //
@@ -47,6 +52,13 @@ type tagsRoot struct {
 	Tags []Tag `json:"objects"`
 }
 
+// tagResourcesRequest updates a tag including empty resources, which are
+// omitted by Tag.
+type tagResourcesRequest struct {
+	*Tag
+	Resources []TagResource `json:"resources"`
+}
+
 func (t Tag) String() string {
 	return Stringify(t)
 }
@@ -54,8 +66,12 @@ func (t Tag) String() string {
 // List provides a list of tags to which the authenticated user has access.
 //
 // CloudSigma API docs: https://cloudsigma-docs.readthedocs.io/en/latest/tags.html#listing
-func (s *TagsService) List(ctx context.Context) ([]Tag, *Response, error) {
+func (s *TagsService) List(ctx context.Context, opts *ListOptions) ([]Tag, *Response, error) {
 	path := fmt.Sprintf("%v/", tagsBasePath)
+	path, err := addOptions(path, opts)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	req, err := s.client.NewRequest(http.MethodGet, path, nil)
 	if err != nil {
@@ -166,3 +182,204 @@ func (s *TagsService) Delete(ctx context.Context, uuid string) (*Response, error
 
 	return s.client.Do(ctx, req, nil)
 }
+
+// EnsureTag returns the tag with the given name, and creates it if there is
+// none. All tags are listed, so a tag past the first page is not created
+// again.
+func (s *TagsService) EnsureTag(ctx context.Context, name string) (*Tag, *Response, error) {
+	if name == "" {
+		return nil, nil, ErrEmptyArgument
+	}
+
+	tags, resp, err := s.List(ctx, &ListOptions{Limit: 0})
+	if err != nil {
+		return nil, resp, err
+	}
+	for i := range tags {
+		if tags[i].Name == name {
+			return &tags[i], resp, nil
+		}
+	}
+
+	tags, resp, err = s.Create(ctx, &TagCreateRequest{Tags: []Tag{{Name: name}}})
+	if err != nil {
+		return nil, resp, err
+	}
+	if len(tags) == 0 {
+		return nil, resp, ErrResourceNotFound
+	}
+	return &tags[0], resp, nil
+}
+
+// AddResources adds resources identified by uuid to a tag. Resources already
+// in the tag are skipped.
+//
+// The tag is read, changed and written back. If the API reports a conflict,
+// or the written tag lacks the change because of a concurrent update, it is
+// retried a few times before ErrConflict is returned.
+//
+// The API has no conditional update, so only the given resources are
+// verified: resources that other clients add to or remove from the tag
+// between the read and the write are reverted by the write. Callers changing
+// the same tag concurrently need to serialize their changes.
+func (s *TagsService) AddResources(ctx context.Context, uuid string, resourceUUIDs ...string) (*Tag, *Response, error) {
+	return s.changeResources(ctx, uuid, resourceUUIDs, true)
+}
+
+// RemoveResources removes resources identified by uuid from a tag. See
+// AddResources for the handling of concurrent updates.
+func (s *TagsService) RemoveResources(ctx context.Context, uuid string, resourceUUIDs ...string) (*Tag, *Response, error) {
+	return s.changeResources(ctx, uuid, resourceUUIDs, false)
+}
+
+func (s *TagsService) changeResources(ctx context.Context, uuid string, resourceUUIDs []string, add bool) (*Tag, *Response, error) {
+	if uuid == "" || len(resourceUUIDs) == 0 {
+		return nil, nil, ErrEmptyArgument
+	}
+	changed := make(map[string]bool, len(resourceUUIDs))
+	for _, r := range resourceUUIDs {
+		changed[r] = true
+	}
+	// done reports whether the resources of the tag include all changed
+	// resources when adding, and none of them when removing.
+	done := func(tag *Tag) bool {
+		count := 0
+		for _, r := range tag.Resources {
+			if changed[r.UUID] {
+				count++
+			}
+		}
+		if add {
+			return count == len(changed)
+		}
+		return count == 0
+	}
+
+	var tag *Tag
+	var resp *Response
+	attempts := 0
+	err := s.client.poll(ctx, func() (bool, error) {
+		if attempts == tagUpdateAttempts {
+			return false, ErrConflict
+		}
+		attempts++
+
+		var err error
+		tag, resp, err = s.Get(ctx, uuid)
+		if err != nil || done(tag) {
+			return true, err
+		}
+
+		resources := make([]TagResource, 0, len(tag.Resources)+len(resourceUUIDs))
+		for _, r := range tag.Resources {
+			if !changed[r.UUID] {
+				resources = append(resources, TagResource{UUID: r.UUID})
+			}
+		}
+		if add {
+			for _, r := range resourceUUIDs {
+				resources = append(resources, TagResource{UUID: r})
+			}
+		}
+
+		req, err := s.client.NewRequest(http.MethodPut, fmt.Sprintf("%v/%v/", tagsBasePath, uuid), &tagResourcesRequest{Tag: tag, Resources: resources})
+		if err != nil {
+			return false, err
+		}
+		updated := new(Tag)
+		resp, err = s.client.Do(ctx, req, updated)
+		var errResp *ErrorResponse
+		if errors.As(err, &errResp) && errResp.Response.StatusCode == http.StatusConflict {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		tag = updated
+		return done(tag), nil
+	})
+	if err != nil {
+		return nil, resp, err
+	}
+	return tag, resp, nil
+}
+
+// ListServers provides detailed information of the servers in a tag
+// identified by uuid.
+//
+// CloudSigma API docs: https://cloudsigma-docs.readthedocs.io/en/latest/tags.html#listing-tagged-resources
+func (s *TagsService) ListServers(ctx context.Context, uuid string) ([]Server, *Response, error) {
+	root := new(serversRoot)
+	resp, err := s.listResources(ctx, uuid, serversBasePath, root)
+	if err != nil {
+		return nil, resp, err
+	}
+	if m := root.Meta; m != nil {
+		resp.Meta = m
+	}
+	return root.Servers, resp, nil
+}
+
+// ListDrives provides detailed information of the drives in a tag
+// identified by uuid.
+//
+// CloudSigma API docs: https://cloudsigma-docs.readthedocs.io/en/latest/tags.html#listing-tagged-resources
+func (s *TagsService) ListDrives(ctx context.Context, uuid string) ([]Drive, *Response, error) {
+	root := new(drivesRoot)
+	resp, err := s.listResources(ctx, uuid, drivesBasePath, root)
+	if err != nil {
+		return nil, resp, err
+	}
+	if m := root.Meta; m != nil {
+		resp.Meta = m
+	}
+	return root.Drives, resp, nil
+}
+
+// ListIPs provides detailed information of the IPs in a tag identified by
+// uuid.
+//
+// CloudSigma API docs: https://cloudsigma-docs.readthedocs.io/en/latest/tags.html#listing-tagged-resources
+func (s *TagsService) ListIPs(ctx context.Context, uuid string) ([]IP, *Response, error) {
+	root := new(ipsRoot)
+	resp, err := s.listResources(ctx, uuid, ipsBasePath, root)
+	if err != nil {
+		return nil, resp, err
+	}
+	if m := root.Meta; m != nil {
+		resp.Meta = m
+	}
+	return root.IPs, resp, nil
+}
+
+// ListVLANs provides detailed information of the VLANs in a tag identified
+// by uuid.
+//
+// CloudSigma API docs: https://cloudsigma-docs.readthedocs.io/en/latest/tags.html#listing-tagged-resources
+func (s *TagsService) ListVLANs(ctx context.Context, uuid string) ([]VLAN, *Response, error) {
+	root := new(vlansRoot)
+	resp, err := s.listResources(ctx, uuid, vlansBasePath, root)
+	if err != nil {
+		return nil, resp, err
+	}
+	if m := root.Meta; m != nil {
+		resp.Meta = m
+	}
+	return root.VLANs, resp, nil
+}
+
+// listResources decodes the tagged resources of the given type into root.
+func (s *TagsService) listResources(ctx context.Context, uuid, resourceType string, root interface{}) (*Response, error) {
+	if uuid == "" {
+		return nil, ErrEmptyArgument
+	}
+
+	path := fmt.Sprintf("%v/%v/%v/", tagsBasePath, uuid, resourceType)
+
+	req, err := s.client.NewRequest(http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(ctx, req, root)
+}
@@ -23,7 +23,7 @@ func TestTags_List(t *testing.T) {
 		},
 	}
 
-	tags, resp, err := client.Tags.List(ctx)
+	tags, resp, err := client.Tags.List(ctx, nil)
 
 	assert.NoError(t, err)
 	assert.Equal(t, expected, tags)
@@ -174,3 +174,207 @@ func TestTags_Delete_emptyUUID(t *testing.T) {
 	assert.Error(t, err)
 	assert.Equal(t, ErrEmptyArgument.Error(), err.Error())
 }
+
+func TestTags_EnsureTag(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/tags/", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		if r.URL.Query().Get("limit") != "0" {
+			_, _ = fmt.Fprint(w, `{"objects":[{"name":"other","uuid":"other-uuid"}],"meta":{"limit":1,"total_count":2}}`)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"objects":[{"name":"other","uuid":"other-uuid"},{"name":"web","uuid":"long-uuid"}]}`)
+	})
+
+	tag, _, err := client.Tags.EnsureTag(ctx, "web")
+
+	assert.NoError(t, err)
+	assert.Equal(t, &Tag{Name: "web", UUID: "long-uuid"}, tag)
+}
+
+func TestTags_EnsureTag_create(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/tags/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			_, _ = fmt.Fprint(w, `{"objects":[{"name":"other","uuid":"other-uuid"}]}`)
+			return
+		}
+		v := new(TagCreateRequest)
+		_ = json.NewDecoder(r.Body).Decode(v)
+		assert.Equal(t, &TagCreateRequest{Tags: []Tag{{Name: "web"}}}, v)
+		assert.Equal(t, http.MethodPost, r.Method)
+		_, _ = fmt.Fprint(w, `{"objects":[{"name":"web","uuid":"long-uuid"}]}`)
+	})
+
+	tag, _, err := client.Tags.EnsureTag(ctx, "web")
+
+	assert.NoError(t, err)
+	assert.Equal(t, &Tag{Name: "web", UUID: "long-uuid"}, tag)
+}
+
+func TestTags_EnsureTag_emptyName(t *testing.T) {
+	_, _, err := client.Tags.EnsureTag(ctx, "")
+
+	assert.Error(t, err)
+	assert.Equal(t, ErrEmptyArgument.Error(), err.Error())
+}
+
+func TestTags_AddResources(t *testing.T) {
+	setup()
+	defer teardown()
+
+	puts := 0
+	mux.HandleFunc("/tags/long-uuid/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			_, _ = fmt.Fprint(w, `{"name":"web","uuid":"long-uuid","resources":[{"uuid":"server-1","res_type":"servers"}]}`)
+			return
+		}
+		assert.Equal(t, http.MethodPut, r.Method)
+		puts++
+		if puts == 1 {
+			w.WriteHeader(http.StatusConflict)
+			_, _ = fmt.Fprint(w, `[{"error_type":"conflict","error_message":"conflict"}]`)
+			return
+		}
+		v := new(Tag)
+		_ = json.NewDecoder(r.Body).Decode(v)
+		assert.Equal(t, []TagResource{{UUID: "server-1"}, {UUID: "server-2"}}, v.Resources)
+		_, _ = fmt.Fprint(w, `{"name":"web","uuid":"long-uuid","resources":[{"uuid":"server-1"},{"uuid":"server-2"}]}`)
+	})
+
+	tag, _, err := client.Tags.AddResources(ctx, "long-uuid", "server-1", "server-2")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, puts)
+	assert.Equal(t, []TagResource{{UUID: "server-1"}, {UUID: "server-2"}}, tag.Resources)
+}
+
+func TestTags_AddResources_unchanged(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/tags/long-uuid/", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		_, _ = fmt.Fprint(w, `{"name":"web","uuid":"long-uuid","resources":[{"uuid":"server-1"}]}`)
+	})
+
+	tag, _, err := client.Tags.AddResources(ctx, "long-uuid", "server-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "long-uuid", tag.UUID)
+}
+
+func TestTags_AddResources_conflict(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/tags/long-uuid/", func(w http.ResponseWriter, r *http.Request) {
+		// a concurrent update drops every change
+		_, _ = fmt.Fprint(w, `{"name":"web","uuid":"long-uuid","resources":[]}`)
+	})
+
+	_, _, err := client.Tags.AddResources(ctx, "long-uuid", "server-1")
+
+	assert.ErrorIs(t, err, ErrConflict)
+}
+
+func TestTags_AddResources_emptyArgument(t *testing.T) {
+	_, _, err := client.Tags.AddResources(ctx, "long-uuid")
+
+	assert.ErrorIs(t, err, ErrEmptyArgument)
+
+	_, _, err = client.Tags.AddResources(ctx, "", "server-1")
+
+	assert.ErrorIs(t, err, ErrEmptyArgument)
+}
+
+func TestTags_RemoveResources(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/tags/long-uuid/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			_, _ = fmt.Fprint(w, `{"name":"web","uuid":"long-uuid","resources":[{"uuid":"server-1"}]}`)
+			return
+		}
+		assert.Equal(t, http.MethodPut, r.Method)
+		v := make(map[string]interface{})
+		_ = json.NewDecoder(r.Body).Decode(&v)
+		assert.Equal(t, []interface{}{}, v["resources"])
+		assert.Equal(t, "web", v["name"])
+		_, _ = fmt.Fprint(w, `{"name":"web","uuid":"long-uuid","resources":[]}`)
+	})
+
+	tag, _, err := client.Tags.RemoveResources(ctx, "long-uuid", "server-1")
+
+	assert.NoError(t, err)
+	assert.Empty(t, tag.Resources)
+}
+
+func TestTags_ListServers(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/tags/long-uuid/servers/", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		_, _ = fmt.Fprint(w, `{"objects":[{"name":"web","uuid":"server-1","status":"running"}],"meta":{"total_count":1}}`)
+	})
+
+	servers, resp, err := client.Tags.ListServers(ctx, "long-uuid")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Server{{Name: "web", UUID: "server-1", Status: "running"}}, servers)
+	assert.Equal(t, 1, resp.Meta.TotalCount)
+}
+
+func TestTags_ListDrives(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/tags/long-uuid/drives/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"objects":[{"name":"disk","uuid":"drive-1","size":1024}]}`)
+	})
+
+	drives, _, err := client.Tags.ListDrives(ctx, "long-uuid")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Drive{{Name: "disk", UUID: "drive-1", Size: 1024}}, drives)
+}
+
+func TestTags_ListIPs(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/tags/long-uuid/ips/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"objects":[{"uuid":"192.0.2.1"}]}`)
+	})
+
+	ips, _, err := client.Tags.ListIPs(ctx, "long-uuid")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []IP{{UUID: "192.0.2.1"}}, ips)
+}
+
+func TestTags_ListVLANs(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/tags/long-uuid/vlans/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"objects":[{"uuid":"vlan-1"}]}`)
+	})
+
+	vlans, _, err := client.Tags.ListVLANs(ctx, "long-uuid")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []VLAN{{UUID: "vlan-1"}}, vlans)
+}
+
+func TestTags_ListServers_emptyUUID(t *testing.T) {
+	_, _, err := client.Tags.ListServers(ctx, "")
+
+	assert.ErrorIs(t, err, ErrEmptyArgument)
+}
@@ -1,5 +1,22 @@
 package cloudsigma
 
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// timestampLayouts lists the time formats used by the CloudSigma API.
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+}
+
 // DriveLicense represents a CloudSigma license attached to the drive.
 type DriveLicense struct {
 	Amount  int           `json:"amount,omitempty"`
@@ -19,3 +36,66 @@ type ResourceLink struct {
 	ResourceURI string `json:"resource_uri,omitempty"`
 	UUID        string `json:"uuid,omitempty"`
 }
+
+// parseTimestamp parses a time value returned by the CloudSigma API. Values
+// without time zone are treated as UTC.
+func parseTimestamp(value string) (time.Time, error) {
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("cloudsigma-sdk-go: cannot parse timestamp %q", value)
+}
+
+// unmarshalWithExtra decodes data into v, a pointer to a struct, and returns
+// the fields of data without matching struct field, or nil if there are
+// none. Field names are matched case-insensitively like encoding/json does.
+func unmarshalWithExtra(data []byte, v interface{}) (map[string]json.RawMessage, error) {
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	known := make(map[string]bool)
+	t := reflect.TypeOf(v).Elem()
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name == "" {
+			name = t.Field(i).Name
+		}
+		known[strings.ToLower(name)] = true
+	}
+	for name := range fields {
+		if known[strings.ToLower(name)] {
+			delete(fields, name)
+		}
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	return fields, nil
+}
+
+// marshalWithExtra encodes v, a pointer to a struct, and adds the fields of
+// extra which v does not encode itself.
+func marshalWithExtra(v interface{}, extra map[string]json.RawMessage) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return data, err
+	}
+
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for name, value := range extra {
+		if _, ok := fields[name]; !ok {
+			fields[name] = value
+		}
+	}
+	return json.Marshal(fields)
+}
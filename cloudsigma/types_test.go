@@ -0,0 +1,59 @@
+package cloudsigma
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypes_parseTimestamp(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected time.Time
+	}{
+		{"2024-05-01T10:20:30+00:00", time.Date(2024, 5, 1, 10, 20, 30, 0, time.UTC)},
+		{"2024-05-01T12:20:30+02:00", time.Date(2024, 5, 1, 10, 20, 30, 0, time.UTC)},
+		{"2024-05-01T10:20:30.123456", time.Date(2024, 5, 1, 10, 20, 30, 123456000, time.UTC)},
+		{"2024-05-01 10:20:30", time.Date(2024, 5, 1, 10, 20, 30, 0, time.UTC)},
+		{"2024-05-01", time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		parsed, err := parseTimestamp(tt.value)
+
+		assert.NoError(t, err, tt.value)
+		assert.True(t, tt.expected.Equal(parsed), "%v: expected %v, got %v", tt.value, tt.expected, parsed)
+	}
+}
+
+func TestTypes_parseTimestamp_invalid(t *testing.T) {
+	_, err := parseTimestamp("yesterday")
+
+	assert.Error(t, err)
+}
+
+func TestTypes_unmarshalWithExtra(t *testing.T) {
+	var link ResourceLink
+
+	extra, err := unmarshalWithExtra([]byte(`{"UUID":"long-uuid","resource_uri":"/uri/","name":"test"}`), &link)
+
+	assert.NoError(t, err)
+	assert.Equal(t, ResourceLink{ResourceURI: "/uri/", UUID: "long-uuid"}, link)
+	assert.Equal(t, map[string]json.RawMessage{"name": json.RawMessage(`"test"`)}, extra)
+
+	extra, err = unmarshalWithExtra([]byte(`{"uuid":"long-uuid"}`), &link)
+
+	assert.NoError(t, err)
+	assert.Nil(t, extra)
+}
+
+func TestTypes_marshalWithExtra(t *testing.T) {
+	data, err := marshalWithExtra(&ResourceLink{UUID: "long-uuid"}, map[string]json.RawMessage{
+		"name": json.RawMessage(`"test"`),
+		"uuid": json.RawMessage(`"ignored"`),
+	})
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"name":"test","uuid":"long-uuid"}`, string(data))
+}
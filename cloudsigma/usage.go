@@ -0,0 +1,93 @@
+package cloudsigma
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	burstUsageBasePath = "burstusage"
+	usageBasePath      = "usage"
+)
+
+// BurstUsageService handles communication with the burst usage related
+// methods of the CloudSigma API.
+//
+// CloudSigma API docs: https://cloudsigma-docs.readthedocs.io/en/latest/billing.html#burst-usage
+type BurstUsageService service
+
+// UsageService handles communication with the usage related methods of the
+// CloudSigma API.
+//
+// CloudSigma API docs: https://cloudsigma-docs.readthedocs.io/en/latest/billing.html#usage
+type UsageService service
+
+// UsageRecord represents the amount of a resource used, or burst, during a
+// billing interval, and its price.
+type UsageRecord struct {
+	Amount      Decimal       `json:"amount"`
+	Interval    int           `json:"interval,omitempty"`
+	Price       Decimal       `json:"price"`
+	Resource    string        `json:"resource,omitempty"`
+	ResourceURI string        `json:"resource_uri,omitempty"`
+	Time        string        `json:"time,omitempty"`
+	User        *ResourceLink `json:"user,omitempty"`
+}
+
+// UsageListOptions specifies the optional parameters to the
+// BurstUsageService.List and UsageService.List.
+type UsageListOptions struct {
+	// Since filters records at or after the given time.
+	Since time.Time `url:"time__gte,omitempty"`
+	// Until filters records before the given time.
+	Until time.Time `url:"time__lt,omitempty"`
+	// Resources filters records based on their resource type, e.g. "cpu".
+	Resources []string `url:"resource,comma,omitempty"`
+
+	ListOptions
+}
+
+type usageRoot struct {
+	Meta         *Meta         `json:"meta,omitempty"`
+	UsageRecords []UsageRecord `json:"objects"`
+}
+
+// List provides a list of burst usage records of the user.
+//
+// CloudSigma API docs: https://cloudsigma-docs.readthedocs.io/en/latest/billing.html#burst-usage
+func (s *BurstUsageService) List(ctx context.Context, opts *UsageListOptions) ([]UsageRecord, *Response, error) {
+	return listUsage(ctx, s.client, burstUsageBasePath, opts)
+}
+
+// List provides a list of usage records of the user.
+//
+// CloudSigma API docs: https://cloudsigma-docs.readthedocs.io/en/latest/billing.html#usage
+func (s *UsageService) List(ctx context.Context, opts *UsageListOptions) ([]UsageRecord, *Response, error) {
+	return listUsage(ctx, s.client, usageBasePath, opts)
+}
+
+func listUsage(ctx context.Context, client *Client, basePath string, opts *UsageListOptions) ([]UsageRecord, *Response, error) {
+	path := fmt.Sprintf("%v/", basePath)
+	path, err := addOptions(path, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := client.NewRequest(http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(usageRoot)
+	resp, err := client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+	if m := root.Meta; m != nil {
+		resp.Meta = m
+	}
+
+	return root.UsageRecords, resp, nil
+}
@@ -0,0 +1,55 @@
+package cloudsigma
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBurstUsage_List(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/burstusage/", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "cpu,mem", r.URL.Query().Get("resource"))
+		_, _ = fmt.Fprint(w, `{"objects":[{"amount":"500","price":"0.00120","resource":"cpu"}],"meta":{"total_count":1}}`)
+	})
+	expected := []UsageRecord{
+		{
+			Amount:   MustParseDecimal("500"),
+			Price:    MustParseDecimal("0.00120"),
+			Resource: "cpu",
+		},
+	}
+
+	records, resp, err := client.BurstUsage.List(ctx, &UsageListOptions{Resources: []string{"cpu", "mem"}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, records)
+	assert.Equal(t, 1, resp.Meta.TotalCount)
+}
+
+func TestUsage_List(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/usage/", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		_, _ = fmt.Fprint(w, `{"objects":[{"amount":"1073741824","price":"0.01","resource":"mem"}],"meta":{"total_count":1}}`)
+	})
+	expected := []UsageRecord{
+		{
+			Amount:   MustParseDecimal("1073741824"),
+			Price:    MustParseDecimal("0.01"),
+			Resource: "mem",
+		},
+	}
+
+	records, _, err := client.Usage.List(ctx, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, records)
+}
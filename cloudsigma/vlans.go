@@ -2,6 +2,7 @@ package cloudsigma
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 )
@@ -23,6 +24,27 @@ type VLAN struct {
 	Subscription *VLANSubscription      `json:"subscription,omitempty"`
 	Tags         []Tag                  `json:"tags,omitempty"`
 	UUID         string                 `json:"uuid,omitempty"`
+
+	// Extra holds API fields unknown to the SDK, see Server.Extra.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// MarshalJSON is a custom marshaller for VLAN. It adds the fields of Extra.
+func (v VLAN) MarshalJSON() ([]byte, error) {
+	type Alias VLAN
+	return marshalWithExtra(Alias(v), v.Extra)
+}
+
+// UnmarshalJSON is a custom unmarshaller for VLAN. It keeps the fields
+// unknown to the SDK in Extra.
+func (v *VLAN) UnmarshalJSON(data []byte) error {
+	type Alias VLAN
+	extra, err := unmarshalWithExtra(data, (*Alias)(v))
+	if err != nil {
+		return err
+	}
+	v.Extra = extra
+	return nil
 }
 
 // VLANSubscription represents a CloudSigma subscription reference.
@@ -36,6 +58,24 @@ type VLANUpdateRequest struct {
 	*VLAN
 }
 
+// MarshalJSON is a custom marshaller for VLANUpdateRequest. Without it the
+// promoted VLAN.MarshalJSON would be called on a nil VLAN.
+func (r VLANUpdateRequest) MarshalJSON() ([]byte, error) {
+	if r.VLAN == nil {
+		return []byte("{}"), nil
+	}
+	return r.VLAN.MarshalJSON()
+}
+
+// UnmarshalJSON is a custom unmarshaller for VLANUpdateRequest. Without
+// it the promoted VLAN.UnmarshalJSON would be called on a nil VLAN.
+func (r *VLANUpdateRequest) UnmarshalJSON(data []byte) error {
+	if r.VLAN == nil {
+		r.VLAN = new(VLAN)
+	}
+	return r.VLAN.UnmarshalJSON(data)
+}
+
 type vlansRoot struct {
 	Meta  *Meta  `json:"meta,omitempty"`
 	VLANs []VLAN `json:"objects"`
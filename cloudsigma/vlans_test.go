@@ -97,6 +97,20 @@ func TestVLANs_Update(t *testing.T) {
 	assert.Equal(t, expected, vlan)
 }
 
+func TestVLANs_MarshalJSON(t *testing.T) {
+	vlan := VLAN{UUID: "long-uuid", Extra: map[string]json.RawMessage{"servers": json.RawMessage(`[]`)}}
+
+	data, err := json.Marshal(vlan)
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"uuid":"long-uuid","servers":[]}`, string(data))
+
+	data, err = json.Marshal(&VLANUpdateRequest{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, `{}`, string(data))
+}
+
 func TestVLANs_Update_emptyUUID(t *testing.T) {
 	input := &VLANUpdateRequest{
 		VLAN: &VLAN{
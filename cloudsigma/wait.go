@@ -0,0 +1,29 @@
+package cloudsigma
+
+import (
+	"context"
+	"time"
+)
+
+// poll calls check repeatedly, waiting the client poll interval between
+// calls, until check reports done, returns an error, or ctx is done.
+func (c *Client) poll(ctx context.Context, check func() (bool, error)) error {
+	ticker := time.NewTicker(c.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		done, err := check()
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
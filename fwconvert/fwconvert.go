@@ -0,0 +1,96 @@
+/*
+Package fwconvert converts firewall rules between CloudSigma firewall
+policies and other formats: iptables-save filter tables, simple nftables
+rulesets and AWS security group JSON.
+
+CloudSigma applies the first matching rule of a policy and accepts packets
+matched by no rule. Chain policies and implicit denies of the imported
+formats are converted to a final drop rule. Constructs which cannot be
+represented exactly, e.g. ICMP, connection tracking or interface matches,
+are reported as warnings. Accept rules using them are skipped, while drop
+rules are converted without them, so a converted policy never accepts
+packets the original one drops. Converted drop rules may drop more packets.
+*/
+package fwconvert
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cloudsigma/cloudsigma-sdk-go/cloudsigma"
+)
+
+// Warning reports a construct which cannot be converted exactly. Line is the
+// 1-based line of the input, or 0 if not applicable.
+type Warning struct {
+	Line    int
+	Message string
+}
+
+func (w Warning) String() string {
+	if w.Line > 0 {
+		return fmt.Sprintf("line %d: %v", w.Line, w.Message)
+	}
+	return w.Message
+}
+
+// Result represents firewall rules converted from another format.
+type Result struct {
+	Rules    []cloudsigma.FirewallPolicyRule
+	Warnings []Warning
+}
+
+func (r *Result) warn(line int, format string, args ...interface{}) {
+	r.Warnings = append(r.Warnings, Warning{Line: line, Message: fmt.Sprintf(format, args...)})
+}
+
+// add appends the rule if it is valid, and reports it otherwise.
+func (r *Result) add(line int, rule cloudsigma.FirewallPolicyRule) {
+	if _, err := cloudsigma.FormatFirewallRule(rule); err != nil {
+		var syntaxErr *cloudsigma.FirewallRuleSyntaxError
+		if errors.As(err, &syntaxErr) {
+			err = errors.New(syntaxErr.Message)
+		}
+		r.warn(line, "skipped rule: %v", err)
+		return
+	}
+	r.Rules = append(r.Rules, rule)
+}
+
+// widen reports the unsupported matches of a rule and returns whether the
+// rule is kept. Accept rules are skipped, as leaving out a match would accept
+// more packets, while drop rules are kept without the matches and drop more
+// packets.
+func (r *Result) widen(line int, rule cloudsigma.FirewallPolicyRule, unsupported []string) bool {
+	if len(unsupported) == 0 {
+		return true
+	}
+	if rule.Action != cloudsigma.FirewallActionDrop {
+		r.warn(line, "skipped rule: %v", unsupported[0])
+		return false
+	}
+	for _, u := range unsupported {
+		r.warn(line, "drop rule widened: %v", u)
+	}
+	return true
+}
+
+// warnFamily reports rules from an input of a single IP family without
+// addresses, which also match packets of the other family in CloudSigma.
+func (r *Result) warnFamily(from int, other string) {
+	count := 0
+	for _, rule := range r.Rules[from:] {
+		if rule.SourceIP == "" && rule.DestinationIP == "" {
+			count++
+		}
+	}
+	if count > 0 {
+		r.warn(0, "%d rule(s) without addresses also match %v packets", count, other)
+	}
+}
+
+// sanitizeComment turns s into a comment valid in a CloudSigma rule.
+func sanitizeComment(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,369 @@
+package fwconvert
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/netip"
+	"strings"
+
+	"github.com/cloudsigma/cloudsigma-sdk-go/cloudsigma"
+)
+
+// iptables chains converted to CloudSigma rule directions.
+var iptablesDirections = map[string]cloudsigma.FirewallDirection{
+	"INPUT":  cloudsigma.FirewallDirectionIn,
+	"OUTPUT": cloudsigma.FirewallDirectionOut,
+}
+
+// FromIPTablesSave converts the filter table of iptables-save output. Rules
+// of the INPUT and OUTPUT chains are converted in order, followed by a drop
+// rule for chains with the DROP policy. Other tables and chains are reported
+// as warnings.
+func FromIPTablesSave(r io.Reader) (*Result, error) {
+	result := &Result{}
+	var table string
+	chains := make(map[string]bool)
+	policies := make(map[string]string)
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		switch {
+		case text == "" || strings.HasPrefix(text, "#"):
+		case strings.HasPrefix(text, "*"):
+			table = text[1:]
+			if table != "filter" {
+				result.warn(line, "table %q is not converted", table)
+			}
+		case text == "COMMIT":
+			table = ""
+		case table != "filter":
+		case strings.HasPrefix(text, ":"):
+			fields := strings.Fields(text[1:])
+			if len(fields) < 2 {
+				return nil, fmt.Errorf("cloudsigma-sdk-go: iptables-save line %d: invalid chain %q", line, text)
+			}
+			policies[fields[0]] = fields[1]
+		default:
+			args, err := splitArgs(text)
+			if err != nil {
+				return nil, fmt.Errorf("cloudsigma-sdk-go: iptables-save line %d: %w", line, err)
+			}
+			if len(args) < 2 || args[0] != "-A" && args[0] != "--append" {
+				result.warn(line, "skipped command %q", text)
+				continue
+			}
+			chain := args[1]
+			direction, ok := iptablesDirections[chain]
+			if !ok {
+				if !chains[chain] {
+					chains[chain] = true
+					result.warn(line, "chain %q is not converted", chain)
+				}
+				continue
+			}
+			parseIPTablesRule(result, line, direction, args[2:])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, chain := range []string{"INPUT", "OUTPUT"} {
+		if policies[chain] == "DROP" {
+			result.Rules = append(result.Rules, cloudsigma.FirewallPolicyRule{
+				Action:    cloudsigma.FirewallActionDrop,
+				Direction: iptablesDirections[chain],
+				Comment:   chain + " policy",
+			})
+		}
+	}
+	result.warnFamily(0, "IPv6")
+	return result, nil
+}
+
+func parseIPTablesRule(result *Result, line int, direction cloudsigma.FirewallDirection, args []string) {
+	rule := cloudsigma.FirewallPolicyRule{Direction: direction}
+	var sources, destinations []string
+	var unsupported []string
+	skip := func(format string, a ...interface{}) {
+		result.warn(line, "skipped rule: "+format, a...)
+	}
+	// ignore records an unsupported match, which is left out of drop rules
+	// and causes other rules to be skipped.
+	ignore := func(format string, a ...interface{}) {
+		unsupported = append(unsupported, fmt.Sprintf(format, a...))
+	}
+
+	for i := 0; i < len(args); i++ {
+		option := args[i]
+		value := ""
+		if option != "!" && i+1 < len(args) {
+			value = args[i+1]
+		}
+		switch option {
+		case "!":
+			ignore("negated matches are not supported")
+			// skip the negated option, its value is skipped below
+			i++
+			if !hasValue(args, i) {
+				continue
+			}
+		case "-s", "--source":
+			sources = strings.Split(value, ",")
+		case "-d", "--destination":
+			destinations = strings.Split(value, ",")
+		case "-p", "--protocol":
+			protocol, ok := iptablesProtocol(value)
+			if !ok {
+				ignore("protocol %q is not supported", value)
+			}
+			rule.Protocol = protocol
+		case "-m", "--match":
+			switch value {
+			case "tcp", "udp", "multiport", "comment":
+			default:
+				ignore("match %q is not supported", value)
+			}
+		case "--sport", "--source-port", "--sports", "--source-ports":
+			rule.SourcePort = iptablesPorts(value)
+		case "--dport", "--destination-port", "--dports", "--destination-ports":
+			rule.DestinationPort = iptablesPorts(value)
+		case "--comment":
+			rule.Comment = sanitizeComment(value)
+		case "-j", "--jump":
+			switch value {
+			case "ACCEPT":
+				rule.Action = cloudsigma.FirewallActionAccept
+			case "DROP":
+				rule.Action = cloudsigma.FirewallActionDrop
+			case "REJECT":
+				rule.Action = cloudsigma.FirewallActionDrop
+				result.warn(line, "REJECT is converted to drop")
+			default:
+				skip("target %q is not supported", value)
+				return
+			}
+		case "--reject-with":
+		default:
+			ignore("option %q is not supported", option)
+			// flags like --syn take no value
+			if !hasValue(args, i) {
+				continue
+			}
+		}
+		i++
+	}
+	if rule.Action == "" {
+		skip("no ACCEPT, DROP or REJECT target")
+		return
+	}
+	if !result.widen(line, rule, unsupported) {
+		return
+	}
+	if len(unsupported) > 0 && rule.Protocol == "" {
+		// ports of an unsupported protocol
+		rule.SourcePort = ""
+		rule.DestinationPort = ""
+	}
+
+	for _, source := range orAny(sources) {
+		for _, destination := range orAny(destinations) {
+			rule.SourceIP = iptablesAddress(source)
+			rule.DestinationIP = iptablesAddress(destination)
+			result.add(line, rule)
+		}
+	}
+}
+
+// hasValue reports whether the option at index i is followed by a value
+// rather than by the next option.
+func hasValue(args []string, i int) bool {
+	return i+1 < len(args) && !strings.HasPrefix(args[i+1], "-")
+}
+
+func iptablesProtocol(protocol string) (cloudsigma.FirewallProtocol, bool) {
+	switch strings.ToLower(protocol) {
+	case "all", "0":
+		return "", true
+	case "tcp", "6":
+		return cloudsigma.FirewallProtocolTCP, true
+	case "udp", "17":
+		return cloudsigma.FirewallProtocolUDP, true
+	}
+	return "", false
+}
+
+// iptablesPorts converts iptables ports like "22", "1000:" or "80,443" to
+// CloudSigma ports. Port 0 is replaced by 1, and all ports by "".
+func iptablesPorts(ports string) string {
+	parts := strings.Split(ports, ",")
+	for i, part := range parts {
+		from, to, isRange := strings.Cut(part, ":")
+		if from == "" || from == "0" {
+			from = "1"
+		}
+		if isRange && to == "" {
+			to = "65535"
+		}
+		if from == "1" && to == "65535" {
+			return ""
+		}
+		parts[i] = from
+		if isRange && to != from {
+			parts[i] += ":" + to
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// iptablesAddress converts an iptables address. Host prefixes like /32 are
+// removed.
+func iptablesAddress(address string) string {
+	prefix, err := netip.ParsePrefix(address)
+	if err != nil {
+		return address
+	}
+	if prefix.IsSingleIP() {
+		return prefix.Addr().String()
+	}
+	return address
+}
+
+func orAny(addresses []string) []string {
+	if len(addresses) == 0 {
+		return []string{""}
+	}
+	return addresses
+}
+
+// ToIPTablesSave writes rules as an iptables-save filter table. Invalid and
+// IPv6 rules are skipped and reported as warnings, as iptables-save covers
+// IPv4 only.
+func ToIPTablesSave(w io.Writer, rules []cloudsigma.FirewallPolicyRule) ([]Warning, error) {
+	result := &Result{}
+	lines := []string{"*filter", ":INPUT ACCEPT [0:0]", ":FORWARD ACCEPT [0:0]", ":OUTPUT ACCEPT [0:0]"}
+	ipv4Only := 0
+	for i, rule := range rules {
+		if _, err := cloudsigma.FormatFirewallRule(rule); err != nil {
+			result.warn(0, "skipped rule %d: %v", i, err)
+			continue
+		}
+		if isIPv6(rule.SourceIP) || isIPv6(rule.DestinationIP) {
+			result.warn(0, "skipped IPv6 rule %d", i)
+			continue
+		}
+		if rule.SourceIP == "" && rule.DestinationIP == "" {
+			ipv4Only++
+		}
+		lines = append(lines, formatIPTablesRule(&rule))
+	}
+	lines = append(lines, "COMMIT")
+	if ipv4Only > 0 {
+		result.warn(0, "%d rule(s) without addresses only match IPv4 packets in iptables", ipv4Only)
+	}
+
+	for _, line := range lines {
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return nil, err
+		}
+	}
+	return result.Warnings, nil
+}
+
+func formatIPTablesRule(rule *cloudsigma.FirewallPolicyRule) string {
+	args := []string{"-A", "INPUT"}
+	if rule.Direction == cloudsigma.FirewallDirectionOut {
+		args[1] = "OUTPUT"
+	}
+	if rule.SourceIP != "" {
+		args = append(args, "-s", rule.SourceIP)
+	}
+	if rule.DestinationIP != "" {
+		args = append(args, "-d", rule.DestinationIP)
+	}
+	if rule.Protocol != "" {
+		args = append(args, "-p", string(rule.Protocol))
+	}
+	if strings.Contains(rule.SourcePort+rule.DestinationPort, ",") {
+		args = append(args, "-m", "multiport")
+		if rule.SourcePort != "" {
+			args = append(args, "--sports", rule.SourcePort)
+		}
+		if rule.DestinationPort != "" {
+			args = append(args, "--dports", rule.DestinationPort)
+		}
+	} else if rule.SourcePort != "" || rule.DestinationPort != "" {
+		args = append(args, "-m", string(rule.Protocol))
+		if rule.SourcePort != "" {
+			args = append(args, "--sport", rule.SourcePort)
+		}
+		if rule.DestinationPort != "" {
+			args = append(args, "--dport", rule.DestinationPort)
+		}
+	}
+	if rule.Comment != "" {
+		args = append(args, "-m", "comment", "--comment", quoteArg(rule.Comment))
+	}
+	target := "ACCEPT"
+	if rule.Action == cloudsigma.FirewallActionDrop {
+		target = "DROP"
+	}
+	return strings.Join(append(args, "-j", target), " ")
+}
+
+func isIPv6(address string) bool {
+	if address == "" {
+		return false
+	}
+	addr, err := netip.ParseAddr(address)
+	if err != nil {
+		prefix, err := netip.ParsePrefix(address)
+		if err != nil {
+			return false
+		}
+		addr = prefix.Addr()
+	}
+	return !addr.Unmap().Is4()
+}
+
+// quoteArg quotes s like iptables-save, escaping backslashes and quotes.
+func quoteArg(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// splitArgs splits a command line into arguments. Arguments may be quoted
+// with double quotes, and backslashes escape the next character.
+func splitArgs(s string) ([]string, error) {
+	var args []string
+	var arg strings.Builder
+	inArg, quoted, escaped := false, false, false
+	for _, r := range s {
+		switch {
+		case escaped:
+			arg.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped, inArg = true, true
+		case r == '"':
+			quoted, inArg = !quoted, true
+		case !quoted && (r == ' ' || r == '\t'):
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		default:
+			arg.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quoted || escaped {
+		return nil, fmt.Errorf("unterminated quote in %q", s)
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+	return args, nil
+}
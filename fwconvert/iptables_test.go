@@ -0,0 +1,144 @@
+package fwconvert
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudsigma/cloudsigma-sdk-go/cloudsigma"
+)
+
+const iptablesSave = `# Generated by iptables-save v1.8.7 on Sat Jun 29 12:00:00 2024
+*nat
+:PREROUTING ACCEPT [0:0]
+-A PREROUTING -p tcp --dport 8080 -j REDIRECT --to-ports 80
+COMMIT
+*filter
+:INPUT DROP [0:0]
+:FORWARD DROP [0:0]
+:OUTPUT ACCEPT [0:0]
+:LOGGING - [0:0]
+-A INPUT -i lo -j ACCEPT
+-A INPUT -m state --state RELATED,ESTABLISHED -j ACCEPT
+-A INPUT -s 10.0.0.0/8,192.0.2.10/32 -p tcp -m tcp --dport 22 -m comment --comment "ssh \"admin\"" -j ACCEPT
+-A INPUT -p tcp -m multiport --dports 80,443,8000:8010 -j ACCEPT
+-A INPUT -p udp -m udp --sport 53 --dport 1024: -j ACCEPT
+-A INPUT -p icmp -j ACCEPT
+-A INPUT ! -s 10.0.0.0/8 -p tcp --dport 5432 -j DROP
+-A INPUT -s 198.51.100.0/24 -j REJECT --reject-with icmp-port-unreachable
+-A INPUT -j LOGGING
+-A FORWARD -j ACCEPT
+-A LOGGING -j LOG
+-A OUTPUT -p tcp -d 203.0.113.25 --dport 25 -j DROP
+COMMIT
+`
+
+func TestFromIPTablesSave(t *testing.T) {
+	result, err := FromIPTablesSave(strings.NewReader(iptablesSave))
+
+	assert.NoError(t, err)
+	assert.Equal(t, []cloudsigma.FirewallPolicyRule{
+		{Action: "accept", Direction: "in", Protocol: "tcp", SourceIP: "10.0.0.0/8", DestinationPort: "22", Comment: `ssh "admin"`},
+		{Action: "accept", Direction: "in", Protocol: "tcp", SourceIP: "192.0.2.10", DestinationPort: "22", Comment: `ssh "admin"`},
+		{Action: "accept", Direction: "in", Protocol: "tcp", DestinationPort: "80,443,8000:8010"},
+		{Action: "accept", Direction: "in", Protocol: "udp", SourcePort: "53", DestinationPort: "1024:65535"},
+		{Action: "drop", Direction: "in", Protocol: "tcp", DestinationPort: "5432"},
+		{Action: "drop", Direction: "in", SourceIP: "198.51.100.0/24"},
+		{Action: "drop", Direction: "out", Protocol: "tcp", DestinationIP: "203.0.113.25", DestinationPort: "25"},
+		{Action: "drop", Direction: "in", Comment: "INPUT policy"},
+	}, result.Rules)
+	assert.Equal(t, []Warning{
+		{Line: 2, Message: `table "nat" is not converted`},
+		{Line: 11, Message: `skipped rule: option "-i" is not supported`},
+		{Line: 12, Message: `skipped rule: match "state" is not supported`},
+		{Line: 16, Message: `skipped rule: protocol "icmp" is not supported`},
+		{Line: 17, Message: "drop rule widened: negated matches are not supported"},
+		{Line: 18, Message: "REJECT is converted to drop"},
+		{Line: 19, Message: `skipped rule: target "LOGGING" is not supported`},
+		{Line: 20, Message: `chain "FORWARD" is not converted`},
+		{Line: 21, Message: `chain "LOGGING" is not converted`},
+		{Line: 0, Message: "4 rule(s) without addresses also match IPv6 packets"},
+	}, result.Warnings)
+	assert.Equal(t, "line 2: table \"nat\" is not converted", result.Warnings[0].String())
+}
+
+func TestFromIPTablesSave_invalid(t *testing.T) {
+	_, err := FromIPTablesSave(strings.NewReader("*filter\n-A INPUT -m comment --comment \"open\n"))
+
+	assert.EqualError(t, err, `cloudsigma-sdk-go: iptables-save line 2: unterminated quote in "-A INPUT -m comment --comment \"open"`)
+
+	result, err := FromIPTablesSave(strings.NewReader("*filter\n-A INPUT -p tcp --dport 70000 -j ACCEPT\n"))
+
+	assert.NoError(t, err)
+	assert.Empty(t, result.Rules)
+	assert.Equal(t, []Warning{{Line: 2, Message: `skipped rule: invalid ports "70000"`}}, result.Warnings)
+}
+
+func TestFromIPTablesSave_widenedDrop(t *testing.T) {
+	save := `*filter
+-A INPUT -i eth0 -s 10.0.0.0/8 -j DROP
+-A INPUT -p sctp --dport 9 -j DROP
+-A INPUT -i eth0 -s 192.0.2.0/24 -j ACCEPT
+-A INPUT -p tcp -m tcp --syn -j ACCEPT
+-A INPUT -p tcp --syn --dport 23 -j DROP
+-A INPUT -j ACCEPT
+COMMIT
+`
+
+	result, err := FromIPTablesSave(strings.NewReader(save))
+
+	assert.NoError(t, err)
+	assert.Equal(t, []cloudsigma.FirewallPolicyRule{
+		{Action: "drop", Direction: "in", SourceIP: "10.0.0.0/8"},
+		{Action: "drop", Direction: "in"},
+		{Action: "drop", Direction: "in", Protocol: "tcp", DestinationPort: "23"},
+		{Action: "accept", Direction: "in"},
+	}, result.Rules)
+	assert.Equal(t, []Warning{
+		{Line: 2, Message: `drop rule widened: option "-i" is not supported`},
+		{Line: 3, Message: `drop rule widened: protocol "sctp" is not supported`},
+		{Line: 4, Message: `skipped rule: option "-i" is not supported`},
+		{Line: 5, Message: `skipped rule: option "--syn" is not supported`},
+		{Line: 6, Message: `drop rule widened: option "--syn" is not supported`},
+		{Line: 0, Message: "3 rule(s) without addresses also match IPv6 packets"},
+	}, result.Warnings)
+}
+
+func TestToIPTablesSave(t *testing.T) {
+	rules, err := cloudsigma.ParseFirewallRules(`
+in accept tcp from 10.0.0.0/8 to any port 22 # ssh "admin"
+in accept tcp to any port 80,443
+in accept udp from any port 53 to any port 1024:65535
+in drop from 2001:db8::/32
+out drop tcp to 203.0.113.25 port 25
+in drop
+`)
+	assert.NoError(t, err)
+	var b bytes.Buffer
+
+	warnings, err := ToIPTablesSave(&b, rules)
+
+	assert.NoError(t, err)
+	assert.Equal(t, `*filter
+:INPUT ACCEPT [0:0]
+:FORWARD ACCEPT [0:0]
+:OUTPUT ACCEPT [0:0]
+-A INPUT -s 10.0.0.0/8 -p tcp -m tcp --dport 22 -m comment --comment "ssh \"admin\"" -j ACCEPT
+-A INPUT -p tcp -m multiport --dports 80,443 -j ACCEPT
+-A INPUT -p udp -m udp --sport 53 --dport 1024:65535 -j ACCEPT
+-A OUTPUT -d 203.0.113.25 -p tcp -m tcp --dport 25 -j DROP
+-A INPUT -j DROP
+COMMIT
+`, b.String())
+	assert.Equal(t, []Warning{
+		{Message: "skipped IPv6 rule 3"},
+		{Message: "3 rule(s) without addresses only match IPv4 packets in iptables"},
+	}, warnings)
+
+	imported, err := FromIPTablesSave(&b)
+
+	assert.NoError(t, err)
+	assert.Equal(t, append(rules[:3:3], rules[4:]...), imported.Rules)
+}
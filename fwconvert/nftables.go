@@ -0,0 +1,480 @@
+package fwconvert
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/cloudsigma/cloudsigma-sdk-go/cloudsigma"
+)
+
+// nftToken is a token of an nftables ruleset. Statements are separated by
+// newline and ";" tokens.
+type nftToken struct {
+	text   string
+	quoted bool
+	line   int
+}
+
+// FromNFTables converts a simple nftables ruleset as printed by
+// "nft list ruleset". Rules of base chains with the input and output filter
+// hooks are converted in order, followed by a drop rule for chains with the
+// drop policy. Sets of addresses, ports and protocols are expanded into
+// several rules. Other chains and unsupported statements are reported as
+// warnings.
+func FromNFTables(r io.Reader) (*Result, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := tokenizeNFTables(string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	p := &nftParser{tokens: tokens, result: &Result{}}
+	if err := p.parseRuleset(); err != nil {
+		return nil, err
+	}
+	return p.result, nil
+}
+
+func tokenizeNFTables(s string) ([]nftToken, error) {
+	var tokens []nftToken
+	runes := []rune(s)
+	line := 1
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\n':
+			tokens = append(tokens, nftToken{text: "\n", line: line})
+			line++
+		case r == '#':
+			for i+1 < len(runes) && runes[i+1] != '\n' {
+				i++
+			}
+		case unicode.IsSpace(r):
+		case strings.ContainsRune("{};,", r):
+			tokens = append(tokens, nftToken{text: string(r), line: line})
+		case r == '"':
+			j := i + 1
+			for j < len(runes) && runes[j] != '"' && runes[j] != '\n' {
+				j++
+			}
+			if j == len(runes) || runes[j] != '"' {
+				return nil, fmt.Errorf("cloudsigma-sdk-go: nftables line %d: unterminated string", line)
+			}
+			tokens = append(tokens, nftToken{text: string(runes[i+1 : j]), quoted: true, line: line})
+			i = j
+		default:
+			j := i
+			for j < len(runes) && !unicode.IsSpace(runes[j]) && !strings.ContainsRune("{};,\"#", runes[j]) {
+				j++
+			}
+			tokens = append(tokens, nftToken{text: string(runes[i:j]), line: line})
+			i = j - 1
+		}
+	}
+	return tokens, nil
+}
+
+type nftParser struct {
+	tokens []nftToken
+	pos    int
+	result *Result
+}
+
+func (p *nftParser) peek() nftToken {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return nftToken{}
+}
+
+func (p *nftParser) next() nftToken {
+	t := p.peek()
+	if p.pos < len(p.tokens) {
+		p.pos++
+	}
+	return t
+}
+
+func (p *nftParser) skipSeparators() {
+	for t := p.peek(); t.text == "\n" || t.text == ";"; t = p.peek() {
+		p.next()
+	}
+}
+
+func (p *nftParser) expect(text string) error {
+	if t := p.next(); t.text != text || t.quoted {
+		return fmt.Errorf("cloudsigma-sdk-go: nftables line %d: expected %q, got %q", t.line, text, t.text)
+	}
+	return nil
+}
+
+// statement returns the tokens up to the next separator outside of braces.
+// It returns false at a closing brace.
+func (p *nftParser) statement() ([]nftToken, bool) {
+	p.skipSeparators()
+	if t := p.peek(); t.text == "}" && !t.quoted || t.text == "" {
+		return nil, false
+	}
+	var tokens []nftToken
+	depth := 0
+	for t := p.peek(); t.text != ""; t = p.peek() {
+		if !t.quoted {
+			if depth == 0 && (t.text == "\n" || t.text == ";" || t.text == "}") {
+				break
+			}
+			switch t.text {
+			case "{":
+				depth++
+			case "}":
+				depth--
+			}
+		}
+		tokens = append(tokens, p.next())
+	}
+	return tokens, true
+}
+
+func (p *nftParser) parseRuleset() error {
+	for {
+		p.skipSeparators()
+		t := p.next()
+		switch t.text {
+		case "":
+			return nil
+		case "table":
+			if err := p.parseTable(); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("cloudsigma-sdk-go: nftables line %d: expected table, got %q", t.line, t.text)
+		}
+	}
+}
+
+func (p *nftParser) parseTable() error {
+	family := "ip"
+	header := p.next()
+	if p.peek().text != "{" {
+		family = header.text
+		header = p.next()
+	}
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	if family != "ip" && family != "ip6" && family != "inet" {
+		p.result.warn(header.line, "table %v %v is not converted", family, header.text)
+		return p.skipBlock()
+	}
+
+	for {
+		p.skipSeparators()
+		t := p.next()
+		switch {
+		case t.text == "}":
+			return nil
+		case t.text == "chain":
+			if err := p.parseChain(family); err != nil {
+				return err
+			}
+		case t.text == "":
+			return fmt.Errorf("cloudsigma-sdk-go: nftables: unterminated table %v", header.text)
+		default:
+			p.result.warn(t.line, "%q in table %v is not converted", t.text, header.text)
+			if err := p.skipDefinition(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// skipBlock skips tokens up to the closing brace of the current block.
+func (p *nftParser) skipBlock() error {
+	for depth := 1; depth > 0; {
+		t := p.next()
+		switch {
+		case t.text == "" && !t.quoted:
+			return fmt.Errorf("cloudsigma-sdk-go: nftables: unterminated block")
+		case t.quoted:
+		case t.text == "{":
+			depth++
+		case t.text == "}":
+			depth--
+		}
+	}
+	return nil
+}
+
+// skipDefinition skips a definition like a set or a counter, which ends
+// with a block.
+func (p *nftParser) skipDefinition() error {
+	for t := p.next(); t.text != "{" || t.quoted; t = p.next() {
+		if t.text == "" {
+			return fmt.Errorf("cloudsigma-sdk-go: nftables: unterminated definition")
+		}
+	}
+	return p.skipBlock()
+}
+
+func (p *nftParser) parseChain(family string) error {
+	name := p.next()
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+
+	var direction cloudsigma.FirewallDirection
+	var policy nftToken
+	first := len(p.result.Rules)
+	hooked := false
+	var statements [][]nftToken
+	for {
+		tokens, ok := p.statement()
+		if !ok {
+			break
+		}
+		switch tokens[0].text {
+		case "type":
+			hooked = true
+			for i := 2; i+1 < len(tokens) && tokens[1].text == "filter"; i++ {
+				if tokens[i].text != "hook" {
+					continue
+				}
+				switch tokens[i+1].text {
+				case "input":
+					direction = cloudsigma.FirewallDirectionIn
+				case "output":
+					direction = cloudsigma.FirewallDirectionOut
+				}
+			}
+		case "policy":
+			if len(tokens) > 1 {
+				policy = tokens[1]
+			}
+		default:
+			statements = append(statements, tokens)
+		}
+	}
+	if err := p.expect("}"); err != nil {
+		return err
+	}
+
+	if direction == "" {
+		if hooked {
+			p.result.warn(name.line, "chain %v is not an input or output chain and is not converted", name.text)
+		} else {
+			p.result.warn(name.line, "regular chain %v is not converted", name.text)
+		}
+		return nil
+	}
+	for _, tokens := range statements {
+		p.parseRule(family, direction, tokens)
+	}
+	if policy.text == "drop" {
+		p.result.Rules = append(p.result.Rules, cloudsigma.FirewallPolicyRule{
+			Action:    cloudsigma.FirewallActionDrop,
+			Direction: direction,
+			Comment:   name.text + " policy",
+		})
+	}
+	switch family {
+	case "ip":
+		p.result.warnFamily(first, "IPv6")
+	case "ip6":
+		p.result.warnFamily(first, "IPv4")
+	}
+	return nil
+}
+
+// nftMatch collects the alternatives of a rule, which are expanded into
+// several CloudSigma rules.
+type nftMatch struct {
+	protocols    []cloudsigma.FirewallProtocol
+	sources      []string
+	destinations []string
+	rule         cloudsigma.FirewallPolicyRule
+}
+
+func (p *nftParser) parseRule(family string, direction cloudsigma.FirewallDirection, tokens []nftToken) {
+	line := tokens[0].line
+	m := &nftMatch{rule: cloudsigma.FirewallPolicyRule{Direction: direction}}
+	var unsupported []string
+	// ignore records an unsupported match, which is left out of drop rules
+	// and causes other rules to be skipped.
+	ignore := func(format string, a ...interface{}) {
+		unsupported = append(unsupported, fmt.Sprintf(format, a...))
+	}
+
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i].text
+		start := i
+		// skipValues skips the possibly negated value or set following the
+		// expression at tokens[start].
+		skipValues := func() {
+			i = start + 2
+			if i < len(tokens) && tokens[i].text == "!=" {
+				i++
+			}
+			if i < len(tokens) && tokens[i].text == "{" {
+				for i < len(tokens) && tokens[i].text != "}" {
+					i++
+				}
+			}
+		}
+		// values returns the value or set of values following the
+		// expression at tokens[i+offset].
+		values := func(offset int) ([]string, bool) {
+			j := i + offset
+			if j >= len(tokens) || tokens[j].text == "!=" {
+				return nil, false
+			}
+			if tokens[j].text != "{" {
+				i = j
+				return []string{tokens[j].text}, true
+			}
+			var set []string
+			for j++; j < len(tokens) && tokens[j].text != "}"; j++ {
+				if tokens[j].text != "," {
+					set = append(set, tokens[j].text)
+				}
+			}
+			i = j
+			return set, len(set) > 0
+		}
+
+		switch {
+		case (t == "ip" || t == "ip6") && i+1 < len(tokens) && (tokens[i+1].text == "saddr" || tokens[i+1].text == "daddr"):
+			field := tokens[i+1].text
+			addresses, ok := values(2)
+			if !ok {
+				ignore("negated or empty %v %v is not supported", t, field)
+				skipValues()
+				continue
+			}
+			if field == "saddr" {
+				m.sources = addresses
+			} else {
+				m.destinations = addresses
+			}
+		case t == "ip" && i+1 < len(tokens) && tokens[i+1].text == "protocol",
+			t == "meta" && i+1 < len(tokens) && tokens[i+1].text == "l4proto":
+			protocols, ok := values(2)
+			if !ok {
+				ignore("negated protocol is not supported")
+				skipValues()
+				continue
+			}
+			m.protocols = nil
+			for _, protocol := range protocols {
+				converted, ok := iptablesProtocol(protocol)
+				if !ok {
+					ignore("protocol %q is not supported", protocol)
+					m.protocols = nil
+					break
+				}
+				m.protocols = append(m.protocols, converted)
+			}
+		case (t == "tcp" || t == "udp") && i+1 < len(tokens) && (tokens[i+1].text == "sport" || tokens[i+1].text == "dport"):
+			field := tokens[i+1].text
+			ports, ok := values(2)
+			if !ok {
+				ignore("negated or empty %v %v is not supported", t, field)
+				skipValues()
+				continue
+			}
+			m.protocols = []cloudsigma.FirewallProtocol{cloudsigma.FirewallProtocol(t)}
+			converted := nftPorts(ports)
+			if field == "sport" {
+				m.rule.SourcePort = converted
+			} else {
+				m.rule.DestinationPort = converted
+			}
+		case t == "counter":
+			// Skip the optional "packets N bytes M".
+			for i+2 < len(tokens) && (tokens[i+1].text == "packets" || tokens[i+1].text == "bytes") {
+				i += 2
+			}
+		case t == "comment" && i+1 < len(tokens):
+			i++
+			m.rule.Comment = sanitizeComment(tokens[i].text)
+		case t == "accept":
+			m.rule.Action = cloudsigma.FirewallActionAccept
+		case t == "drop":
+			m.rule.Action = cloudsigma.FirewallActionDrop
+		case t == "reject":
+			m.rule.Action = cloudsigma.FirewallActionDrop
+			p.result.warn(line, "reject is converted to drop")
+			// Skip the optional "with <type> <code>".
+			if i+1 < len(tokens) && tokens[i+1].text == "with" {
+				i = len(tokens)
+			}
+		default:
+			ignore("%q is not supported", t)
+			// skip the arguments of the expression
+			for i+1 < len(tokens) && !nftExpressionStart(tokens[i+1:]) {
+				i++
+			}
+		}
+	}
+	if m.rule.Action == "" {
+		if len(unsupported) > 0 {
+			p.result.warn(line, "skipped rule: %v", unsupported[0])
+		} else {
+			p.result.warn(line, "skipped rule: no accept, drop or reject verdict")
+		}
+		return
+	}
+	if !p.result.widen(line, m.rule, unsupported) {
+		return
+	}
+
+	if len(m.protocols) == 0 {
+		m.protocols = []cloudsigma.FirewallProtocol{""}
+	}
+	for _, protocol := range m.protocols {
+		for _, source := range orAny(m.sources) {
+			for _, destination := range orAny(m.destinations) {
+				rule := m.rule
+				rule.Protocol = protocol
+				rule.SourceIP = source
+				rule.DestinationIP = destination
+				p.result.add(line, rule)
+			}
+		}
+	}
+}
+
+// nftExpressionStart reports whether tokens start with an expression or a
+// verdict supported by the conversion.
+func nftExpressionStart(tokens []nftToken) bool {
+	next := ""
+	if len(tokens) > 1 {
+		next = tokens[1].text
+	}
+	switch tokens[0].text {
+	case "accept", "drop", "reject", "counter", "comment":
+		return true
+	case "ip":
+		return next == "saddr" || next == "daddr" || next == "protocol"
+	case "ip6":
+		return next == "saddr" || next == "daddr"
+	case "meta":
+		return next == "l4proto"
+	case "tcp", "udp":
+		return next == "sport" || next == "dport"
+	}
+	return false
+}
+
+// nftPorts converts nftables ports like "22" or "1000-2000" to CloudSigma
+// ports.
+func nftPorts(ports []string) string {
+	converted := make([]string, 0, len(ports))
+	for _, port := range ports {
+		converted = append(converted, strings.Replace(port, "-", ":", 1))
+	}
+	return iptablesPorts(strings.Join(converted, ","))
+}
@@ -0,0 +1,137 @@
+package fwconvert
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudsigma/cloudsigma-sdk-go/cloudsigma"
+)
+
+const nftRuleset = `table inet filter {
+	set blocked {
+		type ipv4_addr
+		elements = { 198.51.100.7 }
+	}
+
+	chain input {
+		type filter hook input priority filter; policy drop;
+		iif "lo" accept
+		ct state established,related accept
+		ip saddr { 10.0.0.0/8, 192.0.2.10 } tcp dport { 22, 443 } counter packets 0 bytes 0 accept comment "ssh and https"
+		ip6 saddr 2001:db8::/32 meta l4proto { tcp, udp } th dport 53 accept
+		udp dport 5000-5010 accept
+		ip saddr != 10.0.0.0/8 tcp dport 5432 drop
+		ip saddr 198.51.100.0/24 reject with icmp type host-prohibited
+		icmp type echo-request accept
+		jump logging
+	}
+
+	chain forward {
+		type filter hook forward priority filter; policy drop;
+	}
+
+	chain output {
+		type filter hook output priority filter; policy accept;
+		tcp dport 25 drop # no mail
+	}
+
+	chain logging {
+		log prefix "dropped: "
+	}
+}
+
+table ip nat {
+	chain prerouting {
+		type nat hook prerouting priority dstnat;
+	}
+}
+`
+
+func TestFromNFTables(t *testing.T) {
+	result, err := FromNFTables(strings.NewReader(nftRuleset))
+
+	assert.NoError(t, err)
+	assert.Equal(t, []cloudsigma.FirewallPolicyRule{
+		{Action: "accept", Direction: "in", Protocol: "tcp", SourceIP: "10.0.0.0/8", DestinationPort: "22,443", Comment: "ssh and https"},
+		{Action: "accept", Direction: "in", Protocol: "tcp", SourceIP: "192.0.2.10", DestinationPort: "22,443", Comment: "ssh and https"},
+		{Action: "accept", Direction: "in", Protocol: "udp", DestinationPort: "5000:5010"},
+		{Action: "drop", Direction: "in", Protocol: "tcp", DestinationPort: "5432"},
+		{Action: "drop", Direction: "in", SourceIP: "198.51.100.0/24"},
+		{Action: "drop", Direction: "in", Comment: "input policy"},
+		{Action: "drop", Direction: "out", Protocol: "tcp", DestinationPort: "25"},
+	}, result.Rules)
+	assert.Equal(t, []Warning{
+		{Line: 2, Message: `"set" in table filter is not converted`},
+		{Line: 9, Message: `skipped rule: "iif" is not supported`},
+		{Line: 10, Message: `skipped rule: "ct" is not supported`},
+		{Line: 12, Message: `skipped rule: "th" is not supported`},
+		{Line: 14, Message: "drop rule widened: negated or empty ip saddr is not supported"},
+		{Line: 15, Message: "reject is converted to drop"},
+		{Line: 16, Message: `skipped rule: "icmp" is not supported`},
+		{Line: 17, Message: `skipped rule: "jump" is not supported`},
+		{Line: 20, Message: "chain forward is not an input or output chain and is not converted"},
+		{Line: 29, Message: "regular chain logging is not converted"},
+		{Line: 35, Message: "chain prerouting is not an input or output chain and is not converted"},
+	}, result.Warnings)
+}
+
+func TestFromNFTables_ipFamily(t *testing.T) {
+	ruleset := `table ip filter {
+	chain input { type filter hook input priority 0; policy accept;
+		tcp dport 22 accept; meta l4proto tcp drop
+	}
+}`
+
+	result, err := FromNFTables(strings.NewReader(ruleset))
+
+	assert.NoError(t, err)
+	assert.Equal(t, []cloudsigma.FirewallPolicyRule{
+		{Action: "accept", Direction: "in", Protocol: "tcp", DestinationPort: "22"},
+		{Action: "drop", Direction: "in", Protocol: "tcp"},
+	}, result.Rules)
+	assert.Equal(t, []Warning{{Message: "2 rule(s) without addresses also match IPv6 packets"}}, result.Warnings)
+}
+
+func TestFromNFTables_widenedDrop(t *testing.T) {
+	ruleset := `table inet filter {
+	chain input { type filter hook input priority 0; policy accept;
+		iif "eth0" ip saddr 10.0.0.0/8 drop
+		ct state invalid counter drop
+		meta l4proto { tcp, sctp } drop
+		iif "eth0" ip saddr 192.0.2.0/24 accept
+	}
+}`
+
+	result, err := FromNFTables(strings.NewReader(ruleset))
+
+	assert.NoError(t, err)
+	assert.Equal(t, []cloudsigma.FirewallPolicyRule{
+		{Action: "drop", Direction: "in", SourceIP: "10.0.0.0/8"},
+		{Action: "drop", Direction: "in"},
+		{Action: "drop", Direction: "in"},
+	}, result.Rules)
+	assert.Equal(t, []Warning{
+		{Line: 3, Message: `drop rule widened: "iif" is not supported`},
+		{Line: 4, Message: `drop rule widened: "ct" is not supported`},
+		{Line: 5, Message: `drop rule widened: protocol "sctp" is not supported`},
+		{Line: 6, Message: `skipped rule: "iif" is not supported`},
+	}, result.Warnings)
+}
+
+func TestFromNFTables_invalid(t *testing.T) {
+	tests := map[string]string{
+		"chain input {}":                   `cloudsigma-sdk-go: nftables line 1: expected table, got "chain"`,
+		"table inet filter {\n":            "cloudsigma-sdk-go: nftables: unterminated table filter",
+		"table inet filter { chain input ": `cloudsigma-sdk-go: nftables line 0: expected "{", got ""`,
+		`table inet filter { comment "x }`: "cloudsigma-sdk-go: nftables line 1: unterminated string",
+	}
+	for ruleset, message := range tests {
+		t.Run(ruleset, func(t *testing.T) {
+			_, err := FromNFTables(strings.NewReader(ruleset))
+
+			assert.EqualError(t, err, message)
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package fwconvert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/cloudsigma/cloudsigma-sdk-go/cloudsigma"
+)
+
+// SecurityGroup represents an AWS-style security group, as returned by
+// "aws ec2 describe-security-groups".
+type SecurityGroup struct {
+	GroupID             string         `json:"GroupId"`
+	GroupName           string         `json:"GroupName"`
+	IPPermissions       []IPPermission `json:"IpPermissions"`
+	IPPermissionsEgress []IPPermission `json:"IpPermissionsEgress"`
+}
+
+// IPPermission represents a rule of a security group. FromPort and ToPort
+// are ignored for protocols other than TCP and UDP.
+type IPPermission struct {
+	IPProtocol       string            `json:"IpProtocol"`
+	FromPort         *int              `json:"FromPort,omitempty"`
+	ToPort           *int              `json:"ToPort,omitempty"`
+	IPRanges         []IPRange         `json:"IpRanges"`
+	IPv6Ranges       []IPv6Range       `json:"Ipv6Ranges"`
+	PrefixListIDs    []json.RawMessage `json:"PrefixListIds"`
+	UserIDGroupPairs []json.RawMessage `json:"UserIdGroupPairs"`
+}
+
+// IPRange represents an IPv4 CIDR of a security group rule.
+type IPRange struct {
+	CIDRIP      string `json:"CidrIp"`
+	Description string `json:"Description,omitempty"`
+}
+
+// IPv6Range represents an IPv6 CIDR of a security group rule.
+type IPv6Range struct {
+	CIDRIPv6    string `json:"CidrIpv6"`
+	Description string `json:"Description,omitempty"`
+}
+
+// FromSecurityGroups converts AWS-style security group JSON. The input is
+// either the output of "aws ec2 describe-security-groups", a list of
+// security groups or a single security group.
+//
+// Security groups only allow traffic, so the permissions of all groups are
+// converted to accept rules, followed by a drop rule for each direction.
+// Permissions referencing other security groups or prefix lists, and
+// protocols other than TCP and UDP are reported as warnings.
+func FromSecurityGroups(r io.Reader) (*Result, error) {
+	groups, err := decodeSecurityGroups(r)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{}
+	for _, direction := range []cloudsigma.FirewallDirection{cloudsigma.FirewallDirectionIn, cloudsigma.FirewallDirectionOut} {
+		for _, group := range groups {
+			permissions := group.IPPermissions
+			if direction == cloudsigma.FirewallDirectionOut {
+				permissions = group.IPPermissionsEgress
+			}
+			for i := range permissions {
+				convertIPPermission(result, &group, direction, &permissions[i])
+			}
+		}
+		result.Rules = append(result.Rules, cloudsigma.FirewallPolicyRule{
+			Action:    cloudsigma.FirewallActionDrop,
+			Direction: direction,
+			Comment:   "security group default",
+		})
+	}
+	return result, nil
+}
+
+func decodeSecurityGroups(r io.Reader) ([]SecurityGroup, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimSpace(data)
+
+	var groups []SecurityGroup
+	switch {
+	case bytes.HasPrefix(data, []byte("[")):
+		err = json.Unmarshal(data, &groups)
+	default:
+		var root struct {
+			SecurityGroups []SecurityGroup `json:"SecurityGroups"`
+			SecurityGroup
+		}
+		err = json.Unmarshal(data, &root)
+		groups = root.SecurityGroups
+		if groups == nil {
+			groups = []SecurityGroup{root.SecurityGroup}
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cloudsigma-sdk-go: cannot decode security groups: %w", err)
+	}
+	return groups, nil
+}
+
+func convertIPPermission(result *Result, group *SecurityGroup, direction cloudsigma.FirewallDirection, permission *IPPermission) {
+	name := group.GroupID
+	if name == "" {
+		name = group.GroupName
+	}
+	if len(permission.UserIDGroupPairs) > 0 {
+		result.warn(0, "%v: skipped %d security group reference(s)", name, len(permission.UserIDGroupPairs))
+	}
+	if len(permission.PrefixListIDs) > 0 {
+		result.warn(0, "%v: skipped %d prefix list reference(s)", name, len(permission.PrefixListIDs))
+	}
+
+	rule := cloudsigma.FirewallPolicyRule{Action: cloudsigma.FirewallActionAccept, Direction: direction}
+	switch strings.ToLower(permission.IPProtocol) {
+	case "-1", "all":
+	case "tcp", "6":
+		rule.Protocol = cloudsigma.FirewallProtocolTCP
+	case "udp", "17":
+		rule.Protocol = cloudsigma.FirewallProtocolUDP
+	default:
+		result.warn(0, "%v: skipped permission with protocol %q", name, permission.IPProtocol)
+		return
+	}
+	if rule.Protocol != "" && permission.FromPort != nil && permission.ToPort != nil {
+		rule.DestinationPort = iptablesPorts(strconv.Itoa(*permission.FromPort) + ":" + strconv.Itoa(*permission.ToPort))
+	}
+
+	add := func(cidr, description string) {
+		rule := rule
+		address := iptablesAddress(cidr)
+		if direction == cloudsigma.FirewallDirectionIn {
+			rule.SourceIP = address
+		} else {
+			rule.DestinationIP = address
+		}
+		rule.Comment = sanitizeComment(description)
+		result.add(0, rule)
+	}
+	for _, r := range permission.IPRanges {
+		add(r.CIDRIP, r.Description)
+	}
+	for _, r := range permission.IPv6Ranges {
+		add(r.CIDRIPv6, r.Description)
+	}
+}
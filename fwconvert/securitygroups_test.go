@@ -0,0 +1,89 @@
+package fwconvert
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudsigma/cloudsigma-sdk-go/cloudsigma"
+)
+
+const describeSecurityGroups = `{
+  "SecurityGroups": [
+    {
+      "GroupId": "sg-web",
+      "GroupName": "web",
+      "IpPermissions": [
+        {
+          "IpProtocol": "tcp", "FromPort": 443, "ToPort": 443,
+          "IpRanges": [{"CidrIp": "0.0.0.0/0", "Description": "https"}],
+          "Ipv6Ranges": [{"CidrIpv6": "::/0", "Description": "https"}]
+        },
+        {
+          "IpProtocol": "tcp", "FromPort": 22, "ToPort": 22,
+          "IpRanges": [{"CidrIp": "192.0.2.10/32", "Description": "ssh\nadmin"}],
+          "UserIdGroupPairs": [{"GroupId": "sg-bastion"}]
+        },
+        {
+          "IpProtocol": "icmp", "FromPort": 8, "ToPort": -1,
+          "IpRanges": [{"CidrIp": "0.0.0.0/0"}]
+        }
+      ],
+      "IpPermissionsEgress": [
+        {"IpProtocol": "-1", "IpRanges": [{"CidrIp": "0.0.0.0/0"}]}
+      ]
+    },
+    {
+      "GroupId": "sg-dns",
+      "IpPermissions": [
+        {
+          "IpProtocol": "udp", "FromPort": 0, "ToPort": 65535,
+          "IpRanges": [{"CidrIp": "10.0.0.0/8"}],
+          "PrefixListIds": [{"PrefixListId": "pl-123"}]
+        }
+      ]
+    }
+  ]
+}`
+
+func TestFromSecurityGroups(t *testing.T) {
+	result, err := FromSecurityGroups(strings.NewReader(describeSecurityGroups))
+
+	assert.NoError(t, err)
+	assert.Equal(t, []cloudsigma.FirewallPolicyRule{
+		{Action: "accept", Direction: "in", Protocol: "tcp", SourceIP: "0.0.0.0/0", DestinationPort: "443", Comment: "https"},
+		{Action: "accept", Direction: "in", Protocol: "tcp", SourceIP: "::/0", DestinationPort: "443", Comment: "https"},
+		{Action: "accept", Direction: "in", Protocol: "tcp", SourceIP: "192.0.2.10", DestinationPort: "22", Comment: "ssh admin"},
+		{Action: "accept", Direction: "in", Protocol: "udp", SourceIP: "10.0.0.0/8"},
+		{Action: "drop", Direction: "in", Comment: "security group default"},
+		{Action: "accept", Direction: "out", DestinationIP: "0.0.0.0/0"},
+		{Action: "drop", Direction: "out", Comment: "security group default"},
+	}, result.Rules)
+	assert.Equal(t, []Warning{
+		{Message: "sg-web: skipped 1 security group reference(s)"},
+		{Message: `sg-web: skipped permission with protocol "icmp"`},
+		{Message: "sg-dns: skipped 1 prefix list reference(s)"},
+	}, result.Warnings)
+}
+
+func TestFromSecurityGroups_single(t *testing.T) {
+	single := `{"GroupName": "db", "IpPermissions": [{"IpProtocol": "6", "FromPort": 5432, "ToPort": 5433, "IpRanges": [{"CidrIp": "10.0.0.0/8"}]}]}`
+
+	result, err := FromSecurityGroups(strings.NewReader(single))
+
+	assert.NoError(t, err)
+	assert.Equal(t, cloudsigma.FirewallPolicyRule{Action: "accept", Direction: "in", Protocol: "tcp", SourceIP: "10.0.0.0/8", DestinationPort: "5432:5433"}, result.Rules[0])
+	assert.Len(t, result.Rules, 3)
+
+	result, err = FromSecurityGroups(strings.NewReader("[" + single + "]"))
+
+	assert.NoError(t, err)
+	assert.Len(t, result.Rules, 3)
+}
+
+func TestFromSecurityGroups_invalid(t *testing.T) {
+	_, err := FromSecurityGroups(strings.NewReader(`{"SecurityGroups": {}}`))
+
+	assert.ErrorContains(t, err, "cloudsigma-sdk-go: cannot decode security groups")
+}
@@ -0,0 +1,359 @@
+package nocloud
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+const (
+	sectorSize = 2048
+
+	// Fixed layout of the image: system area, volume descriptors, path
+	// tables and root directories, followed by file data.
+	primaryDescriptorSector   = 16
+	jolietDescriptorSector    = 17
+	terminatorSector          = 18
+	primaryLPathTableSector   = 19
+	primaryMPathTableSector   = 20
+	jolietLPathTableSector    = 21
+	jolietMPathTableSector    = 22
+	primaryRootSector         = 23
+	jolietRootSector          = 24
+	firstFileSector           = 25
+	pathTableSize             = 10
+	directoryRecordHeaderSize = 33
+
+	flagDirectory = 0x02
+
+	rockRidgeID          = "RRIP_1991A"
+	rockRidgeDescription = "THE ROCK RIDGE INTERCHANGE PROTOCOL PROVIDES SUPPORT FOR POSIX FILE SYSTEM SEMANTICS"
+	modeDirectory        = 0o040555
+	modeFile             = 0o100444
+)
+
+// File represents a file in the root directory of an ISO image.
+type File struct {
+	Name string
+	Data []byte
+}
+
+// WriteISO writes an ISO9660 image with the given volume identifier and
+// files to w. All files are placed in the root directory. Long and mixed
+// case file names are preserved with Joliet and Rock Ridge extensions, while
+// the primary volume uses ISO9660 level 1 (8.3) names.
+func WriteISO(w io.Writer, volumeID string, files []File, modTime time.Time) error {
+	if volumeID == "" || len(volumeID) > 16 {
+		return errors.New("nocloud: volume identifier must have 1 to 16 characters")
+	}
+
+	entries, err := isoEntries(files)
+	if err != nil {
+		return err
+	}
+
+	sector := uint32(firstFileSector)
+	for i := range entries {
+		entries[i].sector = sector
+		sector += sectors(len(entries[i].data))
+	}
+	totalSectors := sector
+	modTime = modTime.UTC()
+
+	primaryRoot, err := primaryDirectory(entries, modTime)
+	if err != nil {
+		return err
+	}
+	jolietRoot, err := jolietDirectory(entries, modTime)
+	if err != nil {
+		return err
+	}
+
+	image := make([]byte, 0, int(totalSectors)*sectorSize)
+	image = append(image, make([]byte, primaryDescriptorSector*sectorSize)...)
+	image = append(image, volumeDescriptor(1, strings.ToUpper(volumeID), totalSectors, primaryLPathTableSector, primaryMPathTableSector, primaryRootSector, modTime)...)
+	image = append(image, volumeDescriptor(2, volumeID, totalSectors, jolietLPathTableSector, jolietMPathTableSector, jolietRootSector, modTime)...)
+	image = append(image, terminatorDescriptor()...)
+	image = append(image, pathTable(primaryRootSector, binary.LittleEndian)...)
+	image = append(image, pathTable(primaryRootSector, binary.BigEndian)...)
+	image = append(image, pathTable(jolietRootSector, binary.LittleEndian)...)
+	image = append(image, pathTable(jolietRootSector, binary.BigEndian)...)
+	image = append(image, primaryRoot...)
+	image = append(image, jolietRoot...)
+	for _, e := range entries {
+		image = append(image, pad(e.data)...)
+	}
+
+	_, err = w.Write(image)
+	return err
+}
+
+type isoEntry struct {
+	name        string // original name, used by Rock Ridge
+	primaryName string // ISO9660 level 1 name, e.g. "USER_DAT.;1"
+	data        []byte
+	sector      uint32
+}
+
+func isoEntries(files []File) ([]isoEntry, error) {
+	entries := make([]isoEntry, 0, len(files))
+	names := make(map[string]bool, len(files))
+	primaryNames := make(map[string]bool, len(files))
+	for _, f := range files {
+		if f.Name == "" || strings.ContainsAny(f.Name, "/\x00") || len(f.Name) > 64 {
+			return nil, fmt.Errorf("nocloud: invalid file name %q", f.Name)
+		}
+		if names[f.Name] {
+			return nil, fmt.Errorf("nocloud: duplicated file name %q", f.Name)
+		}
+		names[f.Name] = true
+
+		base, ext := level1Name(f.Name)
+		primaryName := base + "." + ext
+		for i := 1; primaryNames[primaryName]; i++ {
+			suffix := fmt.Sprint(i)
+			trimmed := base
+			if len(trimmed)+len(suffix) > 8 {
+				trimmed = trimmed[:8-len(suffix)]
+			}
+			primaryName = trimmed + suffix + "." + ext
+		}
+		primaryNames[primaryName] = true
+
+		entries = append(entries, isoEntry{name: f.Name, primaryName: primaryName + ";1", data: f.Data})
+	}
+	return entries, nil
+}
+
+// level1Name returns ISO9660 level 1 base name and extension of name.
+func level1Name(name string) (string, string) {
+	base, ext := name, ""
+	if i := strings.LastIndex(name, "."); i > 0 {
+		base, ext = name[:i], name[i+1:]
+	}
+	base, ext = dChars(base), dChars(ext)
+	if len(base) > 8 {
+		base = base[:8]
+	}
+	if len(ext) > 3 {
+		ext = ext[:3]
+	}
+	if base == "" {
+		base = "_"
+	}
+	return base, ext
+}
+
+// dChars converts s to ISO9660 d-characters (A-Z, 0-9 and _).
+func dChars(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(s) {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			continue
+		}
+		b.WriteByte('_')
+	}
+	return b.String()
+}
+
+func primaryDirectory(entries []isoEntry, modTime time.Time) ([]byte, error) {
+	sorted := append([]isoEntry(nil), entries...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].primaryName < sorted[j].primaryName })
+
+	var susp bytes.Buffer
+	susp.Write([]byte{'S', 'P', 7, 1, 0xBE, 0xEF, 0})
+	susp.Write(posixAttributes(modeDirectory, 2))
+	susp.Write(extensionReference())
+
+	var dir bytes.Buffer
+	dir.Write(directoryRecord([]byte{0}, primaryRootSector, sectorSize, flagDirectory, modTime, susp.Bytes()))
+	dir.Write(directoryRecord([]byte{1}, primaryRootSector, sectorSize, flagDirectory, modTime, posixAttributes(modeDirectory, 2)))
+	for _, e := range sorted {
+		su := append(alternateName(e.name), posixAttributes(modeFile, 1)...)
+		dir.Write(directoryRecord([]byte(e.primaryName), e.sector, uint32(len(e.data)), 0, modTime, su))
+	}
+	if dir.Len() > sectorSize {
+		return nil, errors.New("nocloud: too many files for the root directory")
+	}
+	return pad(dir.Bytes()), nil
+}
+
+func jolietDirectory(entries []isoEntry, modTime time.Time) ([]byte, error) {
+	type jolietEntry struct {
+		name []byte
+		isoEntry
+	}
+	sorted := make([]jolietEntry, 0, len(entries))
+	for _, e := range entries {
+		sorted = append(sorted, jolietEntry{name: ucs2(e.name + ";1"), isoEntry: e})
+	}
+	sort.Slice(sorted, func(i, j int) bool { return bytes.Compare(sorted[i].name, sorted[j].name) < 0 })
+
+	var dir bytes.Buffer
+	dir.Write(directoryRecord([]byte{0}, jolietRootSector, sectorSize, flagDirectory, modTime, nil))
+	dir.Write(directoryRecord([]byte{1}, jolietRootSector, sectorSize, flagDirectory, modTime, nil))
+	for _, e := range sorted {
+		dir.Write(directoryRecord(e.name, e.sector, uint32(len(e.data)), 0, modTime, nil))
+	}
+	if dir.Len() > sectorSize {
+		return nil, errors.New("nocloud: too many files for the root directory")
+	}
+	return pad(dir.Bytes()), nil
+}
+
+func directoryRecord(name []byte, sector, size uint32, flags byte, modTime time.Time, systemUse []byte) []byte {
+	length := directoryRecordHeaderSize + len(name)
+	if length%2 != 0 {
+		length++ // padding field
+	}
+	length += len(systemUse)
+
+	record := make([]byte, length)
+	record[0] = byte(length)
+	putBothUint32(record[2:], sector)
+	putBothUint32(record[10:], size)
+	copy(record[18:], recordingTime(modTime))
+	record[25] = flags
+	putBothUint16(record[28:], 1)
+	record[32] = byte(len(name))
+	copy(record[33:], name)
+	copy(record[length-len(systemUse):], systemUse)
+	return record
+}
+
+// volumeDescriptor returns a primary (type 1) or Joliet supplementary
+// (type 2) volume descriptor.
+func volumeDescriptor(descriptorType byte, volumeID string, totalSectors, lPathTable, mPathTable, rootSector uint32, modTime time.Time) []byte {
+	d := make([]byte, sectorSize)
+	d[0] = descriptorType
+	copy(d[1:], "CD001")
+	d[6] = 1
+
+	joliet := descriptorType == 2
+	identifier := func(field []byte, value string) {
+		if joliet {
+			encoded := ucs2(value)
+			for i := 0; i+1 < len(field); i += 2 {
+				field[i], field[i+1] = 0, ' '
+			}
+			copy(field, encoded)
+			return
+		}
+		for i := range field {
+			field[i] = ' '
+		}
+		copy(field, value)
+	}
+
+	identifier(d[8:40], "")
+	identifier(d[40:72], volumeID)
+	putBothUint32(d[80:], totalSectors)
+	if joliet {
+		copy(d[88:], "%/E") // UCS-2 level 3
+	}
+	putBothUint16(d[120:], 1)
+	putBothUint16(d[124:], 1)
+	putBothUint16(d[128:], sectorSize)
+	putBothUint32(d[132:], pathTableSize)
+	binary.LittleEndian.PutUint32(d[140:], lPathTable)
+	binary.BigEndian.PutUint32(d[148:], mPathTable)
+	copy(d[156:190], directoryRecord([]byte{0}, rootSector, sectorSize, flagDirectory, modTime, nil))
+	identifier(d[190:318], "")
+	identifier(d[318:446], "")
+	identifier(d[446:574], "")
+	identifier(d[574:702], "CLOUDSIGMA-SDK-GO")
+	identifier(d[702:739], "")
+	identifier(d[739:776], "")
+	identifier(d[776:813], "")
+	copy(d[813:], descriptorTime(modTime))
+	copy(d[830:], descriptorTime(modTime))
+	copy(d[847:], descriptorTime(time.Time{}))
+	copy(d[864:], descriptorTime(modTime))
+	d[881] = 1
+	return d
+}
+
+func terminatorDescriptor() []byte {
+	d := make([]byte, sectorSize)
+	d[0] = 255
+	copy(d[1:], "CD001")
+	d[6] = 1
+	return d
+}
+
+func pathTable(rootSector uint32, order binary.ByteOrder) []byte {
+	table := make([]byte, sectorSize)
+	table[0] = 1 // identifier length
+	order.PutUint32(table[2:], rootSector)
+	order.PutUint16(table[6:], 1) // parent directory number
+	return table
+}
+
+// posixAttributes returns a Rock Ridge PX entry.
+func posixAttributes(mode, links uint32) []byte {
+	entry := make([]byte, 36)
+	copy(entry, []byte{'P', 'X', 36, 1})
+	putBothUint32(entry[4:], mode)
+	putBothUint32(entry[12:], links)
+	return entry
+}
+
+// alternateName returns a Rock Ridge NM entry.
+func alternateName(name string) []byte {
+	return append([]byte{'N', 'M', byte(5 + len(name)), 1, 0}, name...)
+}
+
+// extensionReference returns a SUSP ER entry identifying Rock Ridge.
+func extensionReference() []byte {
+	entry := []byte{'E', 'R', byte(8 + len(rockRidgeID) + len(rockRidgeDescription)), 1, byte(len(rockRidgeID)), byte(len(rockRidgeDescription)), 0, 1}
+	entry = append(entry, rockRidgeID...)
+	return append(entry, rockRidgeDescription...)
+}
+
+func recordingTime(t time.Time) []byte {
+	return []byte{byte(t.Year() - 1900), byte(t.Month()), byte(t.Day()), byte(t.Hour()), byte(t.Minute()), byte(t.Second()), 0}
+}
+
+func descriptorTime(t time.Time) []byte {
+	if t.IsZero() {
+		return append([]byte("0000000000000000"), 0)
+	}
+	return append([]byte(fmt.Sprintf("%04d%02d%02d%02d%02d%02d%02d", t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond()/1e7)), 0)
+}
+
+func ucs2(s string) []byte {
+	encoded := utf16.Encode([]rune(s))
+	b := make([]byte, 2*len(encoded))
+	for i, r := range encoded {
+		binary.BigEndian.PutUint16(b[2*i:], r)
+	}
+	return b
+}
+
+func putBothUint16(b []byte, v uint16) {
+	binary.LittleEndian.PutUint16(b, v)
+	binary.BigEndian.PutUint16(b[2:], v)
+}
+
+func putBothUint32(b []byte, v uint32) {
+	binary.LittleEndian.PutUint32(b, v)
+	binary.BigEndian.PutUint32(b[4:], v)
+}
+
+func sectors(size int) uint32 {
+	return uint32((size + sectorSize - 1) / sectorSize)
+}
+
+// pad returns a copy of data padded with zeros to whole sectors.
+func pad(data []byte) []byte {
+	padded := make([]byte, int(sectors(len(data)))*sectorSize)
+	copy(padded, data)
+	return padded
+}
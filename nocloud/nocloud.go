@@ -0,0 +1,124 @@
+/*
+Package nocloud builds cloud-init NoCloud seed images and attaches them to
+CloudSigma servers as CD-ROM drives.
+
+The NoCloud datasource reads user-data, meta-data and network-config files
+from a filesystem labelled "cidata", which is useful for images that do not
+support the CloudSigma server context.
+
+cloud-init docs: https://cloudinit.readthedocs.io/en/latest/reference/datasources/nocloud.html
+*/
+package nocloud
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cloudsigma/cloudsigma-sdk-go/cloudsigma"
+)
+
+// VolumeID is the volume identifier expected by the NoCloud datasource.
+const VolumeID = "cidata"
+
+// Seed represents the content of a NoCloud seed image.
+type Seed struct {
+	// UserData is the cloud-init user data, e.g. cloud-config YAML.
+	UserData string
+	// MetaData is the cloud-init meta data YAML. If empty, it is generated
+	// from InstanceID and Hostname.
+	MetaData string
+	// NetworkConfig is the optional network configuration YAML.
+	NetworkConfig string
+
+	// InstanceID is used to generate MetaData. cloud-init runs per-instance
+	// modules again whenever it changes.
+	InstanceID string
+	// Hostname is used to generate MetaData.
+	Hostname string
+
+	// ModTime is the modification time of the files. Defaults to now.
+	ModTime time.Time
+}
+
+// ISO returns the seed as an ISO9660 image with the "cidata" volume label.
+func (s *Seed) ISO() ([]byte, error) {
+	metaData := s.MetaData
+	if metaData == "" {
+		if s.InstanceID == "" {
+			return nil, errors.New("nocloud: either meta data or instance id must be set")
+		}
+		metaData = fmt.Sprintf("instance-id: %v\n", s.InstanceID)
+		if s.Hostname != "" {
+			metaData += fmt.Sprintf("local-hostname: %v\n", s.Hostname)
+		}
+	}
+
+	files := []File{
+		{Name: "meta-data", Data: []byte(metaData)},
+		{Name: "user-data", Data: []byte(s.UserData)},
+	}
+	if s.NetworkConfig != "" {
+		files = append(files, File{Name: "network-config", Data: []byte(s.NetworkConfig)})
+	}
+
+	modTime := s.ModTime
+	if modTime.IsZero() {
+		modTime = time.Now()
+	}
+
+	var buf bytes.Buffer
+	if err := WriteISO(&buf, VolumeID, files, modTime); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Attach uploads the seed image as a CD-ROM drive with the given name, and
+// attaches it to the server identified by serverUUID. The drive is attached
+// to a free IDE channel and booted after all other drives. The server must be
+// stopped for the attachment to take effect. If attaching fails after the
+// upload, the uploaded drive is returned with the error, so it can be deleted.
+func Attach(ctx context.Context, client *cloudsigma.Client, serverUUID, name string, seed *Seed) (*cloudsigma.Server, *cloudsigma.Drive, error) {
+	if client == nil || serverUUID == "" || seed == nil {
+		return nil, nil, cloudsigma.ErrEmptyArgument
+	}
+
+	image, err := seed.ISO()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	drive, _, err := client.Drives.Upload(ctx, bytes.NewReader(image), int64(len(image)))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Update clears the uuid of the request, so send a copy and keep the
+	// uploaded drive intact for error returns
+	update := *drive
+	update.Media = cloudsigma.DriveMediaCDROM
+	update.Name = name
+	updated, _, err := client.Drives.Update(ctx, drive.UUID, &cloudsigma.DriveUpdateRequest{Drive: &update})
+	if err != nil {
+		return nil, drive, err
+	}
+	drive = updated
+
+	server, _, err := client.Servers.Get(ctx, serverUUID)
+	if err != nil {
+		return nil, drive, err
+	}
+	if err := cloudsigma.AttachDrive(server, drive, "ide"); err != nil {
+		return nil, drive, err
+	}
+
+	server, _, err = client.Servers.Update(ctx, serverUUID, &cloudsigma.ServerUpdateRequest{Server: server})
+	if err != nil {
+		return nil, drive, err
+	}
+
+	return server, drive, nil
+}
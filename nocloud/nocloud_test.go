@@ -0,0 +1,199 @@
+package nocloud
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudsigma/cloudsigma-sdk-go/cloudsigma"
+)
+
+var modTime = time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC)
+
+// readRoot returns the file contents of the root directory stored at sector.
+func readRoot(t *testing.T, image []byte, sector int, joliet bool) map[string]string {
+	t.Helper()
+	files := make(map[string]string)
+	dir := image[sector*sectorSize : (sector+1)*sectorSize]
+	for offset := 0; offset < len(dir) && dir[offset] > 0; offset += int(dir[offset]) {
+		record := dir[offset : offset+int(dir[offset])]
+		if record[25]&flagDirectory != 0 {
+			continue
+		}
+		name := record[33 : 33+int(record[32])]
+		if joliet {
+			runes := make([]uint16, len(name)/2)
+			for i := range runes {
+				runes[i] = binary.BigEndian.Uint16(name[2*i:])
+			}
+			name = []byte(string(utf16.Decode(runes)))
+		}
+		start := int(binary.LittleEndian.Uint32(record[2:])) * sectorSize
+		size := int(binary.LittleEndian.Uint32(record[10:]))
+		files[string(name)] = string(image[start : start+size])
+	}
+	return files
+}
+
+func TestSeed_ISO(t *testing.T) {
+	seed := &Seed{
+		UserData:      "#cloud-config\npackages: [nginx]\n",
+		NetworkConfig: "version: 2\n",
+		InstanceID:    "iid-1",
+		Hostname:      "web",
+		ModTime:       modTime,
+	}
+
+	image, err := seed.ISO()
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(image)%sectorSize)
+	assert.Equal(t, "CD001", string(image[primaryDescriptorSector*sectorSize+1:primaryDescriptorSector*sectorSize+6]))
+	assert.Equal(t, "CIDATA", string(bytes.TrimRight(image[primaryDescriptorSector*sectorSize+40:primaryDescriptorSector*sectorSize+72], " ")))
+	assert.Equal(t, "%/E", string(image[jolietDescriptorSector*sectorSize+88:jolietDescriptorSector*sectorSize+91]))
+	assert.Equal(t, ucs2(VolumeID), image[jolietDescriptorSector*sectorSize+40:jolietDescriptorSector*sectorSize+40+2*len(VolumeID)])
+	assert.Equal(t, byte(255), image[terminatorSector*sectorSize])
+
+	expected := map[string]string{
+		"meta-data;1":      "instance-id: iid-1\nlocal-hostname: web\n",
+		"user-data;1":      "#cloud-config\npackages: [nginx]\n",
+		"network-config;1": "version: 2\n",
+	}
+	assert.Equal(t, expected, readRoot(t, image, jolietRootSector, true))
+
+	primary := readRoot(t, image, primaryRootSector, false)
+	assert.Equal(t, expected["meta-data;1"], primary["META_DAT.;1"])
+	assert.Equal(t, expected["network-config;1"], primary["NETWORK_.;1"])
+}
+
+func TestSeed_ISO_emptyMetaData(t *testing.T) {
+	_, err := (&Seed{UserData: "#cloud-config\n"}).ISO()
+
+	assert.Error(t, err)
+}
+
+func TestWriteISO_duplicatedPrimaryNames(t *testing.T) {
+	var buf bytes.Buffer
+	files := []File{{Name: "config-a", Data: []byte("a")}, {Name: "config-b", Data: []byte("b")}}
+
+	err := WriteISO(&buf, VolumeID, files, modTime)
+
+	assert.NoError(t, err)
+	primary := readRoot(t, buf.Bytes(), primaryRootSector, false)
+	assert.Equal(t, map[string]string{"CONFIG_A.;1": "a", "CONFIG_B.;1": "b"}, primary)
+}
+
+func TestWriteISO_invalidFileName(t *testing.T) {
+	err := WriteISO(io.Discard, VolumeID, []File{{Name: "dir/file"}}, modTime)
+
+	assert.Error(t, err)
+}
+
+// rewriteTransport sends all requests to the test server.
+type rewriteTransport struct {
+	target *url.URL
+}
+
+func (t *rewriteTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r.URL.Scheme = t.target.Scheme
+	r.URL.Host = t.target.Host
+	r.URL.Path = "/" + r.URL.Path[len("/api/2.0/"):]
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func TestAttach(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	var uploaded []byte
+	mux.HandleFunc("/drives/upload/", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		uploaded, _ = io.ReadAll(r.Body)
+		_, _ = fmt.Fprint(w, "seed-uuid")
+	})
+	mux.HandleFunc("/drives/seed-uuid/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			var drive cloudsigma.Drive
+			_ = json.NewDecoder(r.Body).Decode(&drive)
+			assert.Equal(t, cloudsigma.DriveMediaCDROM, drive.Media)
+			assert.Equal(t, "seed", drive.Name)
+			_, _ = fmt.Fprint(w, `{"media":"cdrom","name":"seed","uuid":"seed-uuid"}`)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"media":"disk","name":"upload","uuid":"seed-uuid"}`)
+	})
+	mux.HandleFunc("/servers/server-uuid/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			body, _ := io.ReadAll(r.Body)
+			_, _ = w.Write(body)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"name":"web","uuid":"server-uuid","drives":[{"boot_order":1,"dev_channel":"0:0","device":"virtio","drive":{"uuid":"disk-uuid"}}]}`)
+	})
+
+	target, _ := url.Parse(server.URL)
+	client := cloudsigma.NewClient(
+		cloudsigma.NewUsernamePasswordCredentialsProvider("user", "password"),
+		cloudsigma.WithHTTPClient(&http.Client{Transport: &rewriteTransport{target: target}}),
+	)
+
+	s, drive, err := Attach(context.Background(), client, "server-uuid", "seed", &Seed{InstanceID: "iid-1", ModTime: modTime})
+
+	assert.NoError(t, err)
+	assert.Equal(t, cloudsigma.DriveMediaCDROM, drive.Media)
+	assert.Equal(t, 0, len(uploaded)%sectorSize)
+	assert.Len(t, s.Drives, 2)
+	assert.Equal(t, "ide", s.Drives[1].Device)
+	assert.Equal(t, "0:0", s.Drives[1].DevChannel)
+	assert.Equal(t, 2, s.Drives[1].BootOrder)
+	assert.Equal(t, "seed-uuid", s.Drives[1].Drive.UUID)
+}
+
+func TestAttach_updateFailed(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	mux.HandleFunc("/drives/upload/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, "seed-uuid")
+	})
+	mux.HandleFunc("/drives/seed-uuid/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = fmt.Fprint(w, `[{"error_type":"backend","error_message":"failed"}]`)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"media":"disk","name":"upload","uuid":"seed-uuid"}`)
+	})
+
+	target, _ := url.Parse(server.URL)
+	client := cloudsigma.NewClient(
+		cloudsigma.NewUsernamePasswordCredentialsProvider("user", "password"),
+		cloudsigma.WithHTTPClient(&http.Client{Transport: &rewriteTransport{target: target}}),
+	)
+
+	_, drive, err := Attach(context.Background(), client, "server-uuid", "seed", &Seed{InstanceID: "iid-1", ModTime: modTime})
+
+	assert.Error(t, err)
+	if assert.NotNil(t, drive) {
+		assert.Equal(t, "seed-uuid", drive.UUID)
+	}
+}
+
+func TestAttach_emptyArguments(t *testing.T) {
+	_, _, err := Attach(context.Background(), nil, "", "seed", nil)
+
+	assert.ErrorIs(t, err, cloudsigma.ErrEmptyArgument)
+}
@@ -0,0 +1,288 @@
+/*
+Package retention computes which CloudSigma snapshots to keep and which to
+delete according to a grandfather-father-son (GFS) rotation policy.
+*/
+package retention
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cloudsigma/cloudsigma-sdk-go/cloudsigma"
+)
+
+const defaultConcurrency = 4
+
+// Policy represents a snapshot retention policy. Policy is applied to the
+// snapshots of every drive separately. A snapshot is kept if any of the rules
+// keeps it, and deleted otherwise.
+type Policy struct {
+	// KeepLast keeps the given number of the most recent snapshots.
+	KeepLast int
+	// KeepDaily keeps the most recent snapshot of the given number of days.
+	KeepDaily int
+	// KeepWeekly keeps the most recent snapshot of the given number of ISO weeks.
+	KeepWeekly int
+	// KeepMonthly keeps the most recent snapshot of the given number of months.
+	KeepMonthly int
+	// MinAge keeps all snapshots younger than the given duration.
+	MinAge time.Duration
+	// ProtectTags keeps all snapshots tagged with one of the given tags
+	// (matched by tag name or uuid).
+	ProtectTags []string
+	// Location is used to determine days, weeks and months. UTC is used if nil.
+	Location *time.Location
+}
+
+// Decision represents the decision made for a single snapshot.
+type Decision struct {
+	Snapshot cloudsigma.Snapshot
+	// Keep reports whether the snapshot is kept.
+	Keep bool
+	// Reasons explains why the snapshot is kept or deleted.
+	Reasons []string
+}
+
+// Plan represents decisions for a set of snapshots. Decisions are grouped by
+// drive uuid and ordered from the newest snapshot.
+type Plan struct {
+	Decisions []Decision
+}
+
+// Keep returns all snapshots kept by the plan.
+func (p *Plan) Keep() []cloudsigma.Snapshot {
+	return p.filter(true)
+}
+
+// Delete returns all snapshots deleted by the plan.
+func (p *Plan) Delete() []cloudsigma.Snapshot {
+	return p.filter(false)
+}
+
+func (p *Plan) filter(keep bool) []cloudsigma.Snapshot {
+	var snapshots []cloudsigma.Snapshot
+	for _, d := range p.Decisions {
+		if d.Keep == keep {
+			snapshots = append(snapshots, d.Snapshot)
+		}
+	}
+	return snapshots
+}
+
+// Evaluate computes a plan for the given snapshots according to the policy.
+// now is the reference time used for MinAge. Snapshots with a timestamp that
+// cannot be parsed are always kept. A policy without any keep rule is
+// rejected, as it would delete every snapshot.
+func Evaluate(snapshots []cloudsigma.Snapshot, policy Policy, now time.Time) (*Plan, error) {
+	if policy.KeepLast < 0 || policy.KeepDaily < 0 || policy.KeepWeekly < 0 || policy.KeepMonthly < 0 || policy.MinAge < 0 {
+		return nil, fmt.Errorf("retention: policy values cannot be negative")
+	}
+	if policy.KeepLast == 0 && policy.KeepDaily == 0 && policy.KeepWeekly == 0 && policy.KeepMonthly == 0 &&
+		policy.MinAge == 0 && len(policy.ProtectTags) == 0 {
+		return nil, fmt.Errorf("retention: policy has no keep rule and would delete every snapshot")
+	}
+	location := policy.Location
+	if location == nil {
+		location = time.UTC
+	}
+
+	protected := make(map[string]bool, len(policy.ProtectTags))
+	for _, tag := range policy.ProtectTags {
+		protected[tag] = true
+	}
+
+	groups := make(map[string][]entry)
+	for _, s := range snapshots {
+		e := entry{snapshot: s}
+		e.timestamp, e.timestampErr = s.ParsedTimestamp()
+		e.timestamp = e.timestamp.In(location)
+		driveUUID := ""
+		if s.Drive != nil {
+			driveUUID = s.Drive.UUID
+		}
+		groups[driveUUID] = append(groups[driveUUID], e)
+	}
+	driveUUIDs := make([]string, 0, len(groups))
+	for driveUUID := range groups {
+		driveUUIDs = append(driveUUIDs, driveUUID)
+	}
+	sort.Strings(driveUUIDs)
+
+	plan := new(Plan)
+	for _, driveUUID := range driveUUIDs {
+		entries := groups[driveUUID]
+		sort.SliceStable(entries, func(i, j int) bool {
+			return entries[i].timestamp.After(entries[j].timestamp)
+		})
+
+		rules := []bucketRule{
+			{name: "daily", count: policy.KeepDaily, key: func(t time.Time) string { return t.Format("2006-01-02") }},
+			{name: "weekly", count: policy.KeepWeekly, key: func(t time.Time) string {
+				year, week := t.ISOWeek()
+				return fmt.Sprintf("%d-W%02d", year, week)
+			}},
+			{name: "monthly", count: policy.KeepMonthly, key: func(t time.Time) string { return t.Format("2006-01") }},
+		}
+		for i := range rules {
+			rules[i].seen = make(map[string]bool)
+		}
+
+		last := 0
+		for _, e := range entries {
+			d := Decision{Snapshot: e.snapshot}
+			if e.timestampErr != nil {
+				d.Reasons = append(d.Reasons, fmt.Sprintf("unparsable timestamp %q", e.snapshot.Timestamp))
+				plan.Decisions = append(plan.Decisions, keep(d))
+				continue
+			}
+
+			for _, tag := range e.snapshot.Tags {
+				if protected[tag.Name] || protected[tag.UUID] {
+					d.Reasons = append(d.Reasons, fmt.Sprintf("protected by tag %q", tagName(tag)))
+				}
+			}
+			if age := now.Sub(e.timestamp); age < policy.MinAge {
+				d.Reasons = append(d.Reasons, fmt.Sprintf("younger than minimum age %v", policy.MinAge))
+			}
+			if last < policy.KeepLast {
+				last++
+				d.Reasons = append(d.Reasons, fmt.Sprintf("last %d/%d", last, policy.KeepLast))
+			}
+			for i := range rules {
+				if reason, ok := rules[i].apply(e.timestamp); ok {
+					d.Reasons = append(d.Reasons, reason)
+				}
+			}
+
+			if len(d.Reasons) > 0 {
+				plan.Decisions = append(plan.Decisions, keep(d))
+				continue
+			}
+			d.Reasons = append(d.Reasons, "not matched by any retention rule")
+			plan.Decisions = append(plan.Decisions, d)
+		}
+	}
+
+	return plan, nil
+}
+
+// Deleter deletes a snapshot identified by uuid. It is implemented by
+// cloudsigma.SnapshotsService.
+type Deleter interface {
+	Delete(ctx context.Context, uuid string) (*cloudsigma.Response, error)
+}
+
+// ApplyOptions specifies the optional parameters to Apply.
+type ApplyOptions struct {
+	// Concurrency limits the number of concurrent delete requests. Defaults to 4.
+	Concurrency int
+	// DryRun reports the snapshots to delete without deleting them.
+	DryRun bool
+}
+
+// Result represents the outcome of deleting a single snapshot.
+type Result struct {
+	Snapshot cloudsigma.Snapshot
+	// Deleted reports whether the snapshot was deleted. It is false for
+	// dry-runs and failed deletions.
+	Deleted bool
+	Err     error
+}
+
+// Apply deletes all snapshots the plan does not keep, with bounded
+// concurrency. The results are in the order of Plan.Delete. If some deletions
+// fail, the returned error is a *cloudsigma.BulkError.
+func Apply(ctx context.Context, deleter Deleter, plan *Plan, opts *ApplyOptions) ([]Result, error) {
+	if deleter == nil || plan == nil {
+		return nil, cloudsigma.ErrEmptyArgument
+	}
+	if opts == nil {
+		opts = &ApplyOptions{}
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+
+	snapshots := plan.Delete()
+	results := make([]Result, len(snapshots))
+	for i, s := range snapshots {
+		results[i].Snapshot = s
+	}
+	if opts.DryRun {
+		return results, nil
+	}
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, concurrency)
+	for i := range results {
+		wg.Add(1)
+		go func(r *Result) {
+			defer wg.Done()
+			select {
+			case semaphore <- struct{}{}:
+				defer func() { <-semaphore }()
+			case <-ctx.Done():
+				r.Err = ctx.Err()
+				return
+			}
+			_, r.Err = deleter.Delete(ctx, r.Snapshot.UUID)
+			r.Deleted = r.Err == nil
+		}(&results[i])
+	}
+	wg.Wait()
+
+	var failures []cloudsigma.BulkFailure
+	for _, r := range results {
+		if r.Err != nil {
+			failures = append(failures, cloudsigma.BulkFailure{UUID: r.Snapshot.UUID, Err: r.Err})
+		}
+	}
+	if len(failures) > 0 {
+		return results, &cloudsigma.BulkError{Failures: failures}
+	}
+
+	return results, nil
+}
+
+type entry struct {
+	snapshot     cloudsigma.Snapshot
+	timestamp    time.Time
+	timestampErr error
+}
+
+// bucketRule keeps the newest snapshot of the first count time buckets.
+type bucketRule struct {
+	name  string
+	count int
+	key   func(time.Time) string
+	seen  map[string]bool
+}
+
+// apply must be called with timestamps ordered from the newest.
+func (r *bucketRule) apply(t time.Time) (string, bool) {
+	if len(r.seen) >= r.count {
+		return "", false
+	}
+	key := r.key(t)
+	if r.seen[key] {
+		return "", false
+	}
+	r.seen[key] = true
+	return fmt.Sprintf("%v %d/%d (%v)", r.name, len(r.seen), r.count, key), true
+}
+
+func keep(d Decision) Decision {
+	d.Keep = true
+	return d
+}
+
+func tagName(tag cloudsigma.Tag) string {
+	if tag.Name != "" {
+		return tag.Name
+	}
+	return tag.UUID
+}
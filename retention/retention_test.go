@@ -0,0 +1,168 @@
+package retention
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudsigma/cloudsigma-sdk-go/cloudsigma"
+)
+
+var now = time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC)
+
+// nightly returns a snapshot for each of the last days, starting yesterday.
+func nightly(driveUUID string, days int) []cloudsigma.Snapshot {
+	var snapshots []cloudsigma.Snapshot
+	for i := 1; i <= days; i++ {
+		timestamp := now.AddDate(0, 0, -i).Truncate(24 * time.Hour).Add(2 * time.Hour)
+		snapshots = append(snapshots, cloudsigma.Snapshot{
+			Drive:     &cloudsigma.Drive{UUID: driveUUID},
+			Timestamp: timestamp.Format(time.RFC3339),
+			UUID:      driveUUID + "-" + timestamp.Format("2006-01-02"),
+		})
+	}
+	return snapshots
+}
+
+func uuids(snapshots []cloudsigma.Snapshot) []string {
+	var result []string
+	for _, s := range snapshots {
+		result = append(result, s.UUID)
+	}
+	return result
+}
+
+func TestEvaluate_keepLast(t *testing.T) {
+	plan, err := Evaluate(nightly("drive", 5), Policy{KeepLast: 2}, now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"drive-2024-06-29", "drive-2024-06-28"}, uuids(plan.Keep()))
+	assert.Equal(t, []string{"drive-2024-06-27", "drive-2024-06-26", "drive-2024-06-25"}, uuids(plan.Delete()))
+	assert.Equal(t, []string{"last 1/2"}, plan.Decisions[0].Reasons)
+	assert.Equal(t, []string{"not matched by any retention rule"}, plan.Decisions[4].Reasons)
+}
+
+func TestEvaluate_gfs(t *testing.T) {
+	plan, err := Evaluate(nightly("drive", 90), Policy{KeepDaily: 7, KeepWeekly: 4, KeepMonthly: 3}, now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		// daily
+		"drive-2024-06-29", "drive-2024-06-28", "drive-2024-06-27", "drive-2024-06-26",
+		"drive-2024-06-25", "drive-2024-06-24", "drive-2024-06-23",
+		// weekly, 2024-06-29 and 2024-06-23 are already kept
+		"drive-2024-06-16", "drive-2024-06-09",
+		// monthly, 2024-06-29 is already kept
+		"drive-2024-05-31", "drive-2024-04-30",
+	}, uuids(plan.Keep()))
+	assert.Len(t, plan.Delete(), 90-11)
+	assert.Equal(t, []string{"daily 1/7 (2024-06-29)", "weekly 1/4 (2024-W26)", "monthly 1/3 (2024-06)"}, plan.Decisions[0].Reasons)
+}
+
+func TestEvaluate_minAgeAndTags(t *testing.T) {
+	snapshots := nightly("drive", 5)
+	snapshots[4].Tags = []cloudsigma.Tag{{Name: "golden", UUID: "tag-uuid"}}
+
+	plan, err := Evaluate(snapshots, Policy{MinAge: 72 * time.Hour, ProtectTags: []string{"tag-uuid"}}, now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"drive-2024-06-29", "drive-2024-06-28", "drive-2024-06-25"}, uuids(plan.Keep()))
+	assert.Equal(t, []string{"protected by tag \"golden\""}, plan.Decisions[4].Reasons)
+}
+
+func TestEvaluate_groupedByDrive(t *testing.T) {
+	snapshots := append(nightly("b", 3), nightly("a", 3)...)
+
+	plan, err := Evaluate(snapshots, Policy{KeepLast: 1}, now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a-2024-06-29", "b-2024-06-29"}, uuids(plan.Keep()))
+	assert.Equal(t, "a", plan.Decisions[0].Snapshot.Drive.UUID)
+}
+
+func TestEvaluate_unparsableTimestamp(t *testing.T) {
+	snapshots := []cloudsigma.Snapshot{{UUID: "broken", Timestamp: "yesterday"}}
+
+	plan, err := Evaluate(snapshots, Policy{KeepDaily: 1}, now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"broken"}, uuids(plan.Keep()))
+}
+
+func TestEvaluate_negativePolicy(t *testing.T) {
+	_, err := Evaluate(nil, Policy{KeepLast: -1}, now)
+
+	assert.Error(t, err)
+}
+
+func TestEvaluate_emptyPolicy(t *testing.T) {
+	_, err := Evaluate(nightly("drive", 3), Policy{Location: time.UTC}, now)
+
+	assert.EqualError(t, err, "retention: policy has no keep rule and would delete every snapshot")
+}
+
+type fakeDeleter struct {
+	mu       sync.Mutex
+	deleted  []string
+	fail     map[string]error
+	active   int32
+	maxQueue int32
+}
+
+func (f *fakeDeleter) Delete(_ context.Context, uuid string) (*cloudsigma.Response, error) {
+	active := atomic.AddInt32(&f.active, 1)
+	defer atomic.AddInt32(&f.active, -1)
+	f.mu.Lock()
+	if active > f.maxQueue {
+		f.maxQueue = active
+	}
+	f.mu.Unlock()
+	time.Sleep(time.Millisecond)
+
+	if err := f.fail[uuid]; err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	f.deleted = append(f.deleted, uuid)
+	f.mu.Unlock()
+	return nil, nil
+}
+
+func TestApply(t *testing.T) {
+	plan, _ := Evaluate(nightly("drive", 20), Policy{KeepLast: 5}, now)
+	failure := errors.New("drive is busy")
+	deleter := &fakeDeleter{fail: map[string]error{"drive-2024-06-10": failure}}
+
+	results, err := Apply(context.Background(), deleter, plan, &ApplyOptions{Concurrency: 2})
+
+	assert.Len(t, results, 15)
+	assert.Len(t, deleter.deleted, 14)
+	assert.LessOrEqual(t, deleter.maxQueue, int32(2))
+	var bulkErr *cloudsigma.BulkError
+	assert.ErrorAs(t, err, &bulkErr)
+	assert.Equal(t, []cloudsigma.BulkFailure{{UUID: "drive-2024-06-10", Err: failure}}, bulkErr.Failures)
+	assert.True(t, results[0].Deleted)
+}
+
+func TestApply_dryRun(t *testing.T) {
+	plan, _ := Evaluate(nightly("drive", 3), Policy{KeepLast: 1}, now)
+	deleter := &fakeDeleter{}
+
+	results, err := Apply(context.Background(), deleter, plan, &ApplyOptions{DryRun: true})
+
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	assert.False(t, results[0].Deleted)
+	assert.Empty(t, deleter.deleted)
+}
+
+func TestApply_emptyArguments(t *testing.T) {
+	_, err := Apply(context.Background(), nil, nil, nil)
+
+	assert.ErrorIs(t, err, cloudsigma.ErrEmptyArgument)
+}
@@ -0,0 +1,192 @@
+/*
+Package servercontext provides a client for reading the CloudSigma server
+context from inside a guest.
+
+CloudSigma exposes the server definition, including its meta, to the guest
+over a virtual serial port. A request is a context path wrapped in "<\n" and
+"\n>", and the reply is a single line JSON value terminated by the EOT
+character.
+
+CloudSigma docs: https://cloudsigma-docs.readthedocs.io/en/latest/server_context.html
+*/
+package servercontext
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/cloudsigma/cloudsigma-sdk-go/cloudsigma"
+)
+
+const (
+	// DefaultDevice is the serial device the server context is exposed on.
+	DefaultDevice = "/dev/ttyS1"
+	// DefaultTimeout is the default time to wait for a single reply.
+	DefaultTimeout = 5 * time.Second
+
+	endOfTransmission = '\x04'
+)
+
+// ErrTimeout is returned when the server context does not reply in time.
+var ErrTimeout = errors.New("servercontext: timeout waiting for reply")
+
+// Opener opens a connection to the server context device.
+type Opener func() (io.ReadWriteCloser, error)
+
+// A Client reads the server context over a serial device.
+type Client struct {
+	device  string
+	timeout time.Duration
+	open    Opener
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithDevice configures Client to use a specific serial device, e.g. a pty
+// or a named pipe for testing.
+func WithDevice(device string) Option {
+	return func(client *Client) {
+		client.device = device
+	}
+}
+
+// WithTimeout configures Client to wait at most timeout for a single reply.
+func WithTimeout(timeout time.Duration) Option {
+	return func(client *Client) {
+		client.timeout = timeout
+	}
+}
+
+// WithOpener configures Client to use a custom connection instead of opening
+// the serial device.
+func WithOpener(open Opener) Option {
+	return func(client *Client) {
+		client.open = open
+	}
+}
+
+// New returns a new server context client.
+func New(opts ...Option) *Client {
+	c := &Client{
+		device:  DefaultDevice,
+		timeout: DefaultTimeout,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.open == nil {
+		c.open = func() (io.ReadWriteCloser, error) {
+			return os.OpenFile(c.device, os.O_RDWR, 0)
+		}
+	}
+	return c
+}
+
+// Raw sends a request for the context path and returns the raw reply. An
+// empty path requests the whole server context, and paths like "/meta/" or
+// "/meta/ssh_public_key" request a part of it.
+func (c *Client) Raw(ctx context.Context, path string) ([]byte, error) {
+	conn, err := c.open()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	type result struct {
+		data []byte
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		if _, err := fmt.Fprintf(conn, "<\n%v\n>", path); err != nil {
+			done <- result{err: err}
+			return
+		}
+		data, err := readReply(bufio.NewReader(conn))
+		done <- result{data: data, err: err}
+	}()
+
+	timer := time.NewTimer(c.timeout)
+	defer timer.Stop()
+
+	select {
+	case r := <-done:
+		return r.data, r.err
+	case <-timer.C:
+		return nil, ErrTimeout
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// readReply reads a single reply terminated by EOT. Replies may span several
+// lines, e.g. raw cloud-init user data.
+func readReply(r io.ByteReader) ([]byte, error) {
+	var data []byte
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			if errors.Is(err, io.EOF) && len(data) > 0 {
+				return data, nil
+			}
+			return nil, err
+		}
+		if b == endOfTransmission {
+			return data, nil
+		}
+		data = append(data, b)
+	}
+}
+
+// Get requests the context path and decodes the JSON reply into v. A reply
+// which is not valid JSON is stored as is if v is a *string.
+func (c *Client) Get(ctx context.Context, path string, v interface{}) error {
+	data, err := c.Raw(ctx, path)
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(data, v)
+	if err != nil {
+		if s, ok := v.(*string); ok {
+			*s = string(data)
+			return nil
+		}
+		return fmt.Errorf("servercontext: cannot decode reply for %q: %w", path, err)
+	}
+	return nil
+}
+
+// Server requests the whole server context and decodes it into a Server.
+// Meta values listed in base64_fields are decoded.
+func (c *Client) Server(ctx context.Context) (*cloudsigma.Server, error) {
+	server := new(cloudsigma.Server)
+	if err := c.Get(ctx, "", server); err != nil {
+		return nil, err
+	}
+	meta, err := cloudsigma.DecodeBase64Meta(server.Meta)
+	if err != nil {
+		return nil, err
+	}
+	server.Meta = meta
+	return server, nil
+}
+
+// Meta requests the server meta. Meta values listed in base64_fields are
+// decoded.
+func (c *Client) Meta(ctx context.Context) (map[string]interface{}, error) {
+	meta := make(map[string]interface{})
+	if err := c.Get(ctx, "/meta/", &meta); err != nil {
+		return nil, err
+	}
+	return cloudsigma.DecodeBase64Meta(meta)
+}
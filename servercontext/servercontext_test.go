@@ -0,0 +1,148 @@
+package servercontext
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"io"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeSerial returns an Opener serving replies for context paths over a pipe.
+// The requested paths are sent to the requests channel.
+func fakeSerial(replies map[string]string, requests chan<- string) Opener {
+	return func() (io.ReadWriteCloser, error) {
+		guest, host := net.Pipe()
+		go func() {
+			defer func() { _ = host.Close() }()
+			r := bufio.NewReader(host)
+			if _, err := r.ReadString('\n'); err != nil { // "<\n"
+				return
+			}
+			path, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			_, _ = r.ReadByte() // ">"
+			path = strings.TrimSuffix(path, "\n")
+			if requests != nil {
+				requests <- path
+			}
+			reply, ok := replies[path]
+			if !ok {
+				<-time.After(time.Second)
+				return
+			}
+			_, _ = io.WriteString(host, reply+"\x04\n")
+		}()
+		return guest, nil
+	}
+}
+
+func TestClient_Server(t *testing.T) {
+	userData := base64.StdEncoding.EncodeToString([]byte("#cloud-config\n"))
+	replies := map[string]string{
+		"": `{"cpu":2000,"mem":536870912,"meta":{"base64_fields":"cloudinit-user-data","cloudinit-user-data":"` + userData + `","ssh_public_key":"ssh-ed25519 AAAA"},"name":"web","uuid":"server-uuid"}`,
+	}
+	requests := make(chan string, 1)
+	client := New(WithOpener(fakeSerial(replies, requests)))
+
+	server, err := client.Server(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, "", <-requests)
+	assert.Equal(t, "server-uuid", server.UUID)
+	assert.Equal(t, 2000, server.CPU)
+	assert.Equal(t, "#cloud-config\n", server.Meta["cloudinit-user-data"])
+	assert.Equal(t, "ssh-ed25519 AAAA", server.Meta["ssh_public_key"])
+}
+
+func TestClient_Meta(t *testing.T) {
+	replies := map[string]string{
+		"/meta/": `{"base64_fields":"secret","secret":"c2VjcmV0","role":"db"}`,
+	}
+	client := New(WithOpener(fakeSerial(replies, nil)))
+
+	meta, err := client.Meta(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"base64_fields": "secret", "secret": "secret", "role": "db"}, meta)
+}
+
+func TestClient_Get_subPath(t *testing.T) {
+	replies := map[string]string{
+		"/name":       `web`,
+		"/nics/0/mac": `"22:aa:bb:cc:dd:ee"`,
+		"/smp":        `2`,
+	}
+	client := New(WithOpener(fakeSerial(replies, nil)))
+	var name, mac string
+	var smp int
+
+	assert.NoError(t, client.Get(context.Background(), "/name", &name))
+	assert.NoError(t, client.Get(context.Background(), "/nics/0/mac", &mac))
+	assert.NoError(t, client.Get(context.Background(), "/smp", &smp))
+	assert.Equal(t, "web", name)
+	assert.Equal(t, "22:aa:bb:cc:dd:ee", mac)
+	assert.Equal(t, 2, smp)
+}
+
+func TestClient_Raw_multiLine(t *testing.T) {
+	userData := "#cloud-config\npackages:\n  - nginx\n"
+	replies := map[string]string{
+		"/meta/cloudinit-user-data": userData,
+		"/meta/":                    "{\n  \"role\": \"db\"\n}",
+	}
+	client := New(WithOpener(fakeSerial(replies, nil)))
+
+	data, err := client.Raw(context.Background(), "/meta/cloudinit-user-data")
+
+	assert.NoError(t, err)
+	assert.Equal(t, userData, string(data))
+
+	meta, err := client.Meta(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"role": "db"}, meta)
+}
+
+func TestClient_Get_invalidJSON(t *testing.T) {
+	client := New(WithOpener(fakeSerial(map[string]string{"/smp": `two`}, nil)))
+	var smp int
+
+	err := client.Get(context.Background(), "/smp", &smp)
+
+	assert.Error(t, err)
+}
+
+func TestClient_Raw_timeout(t *testing.T) {
+	client := New(WithOpener(fakeSerial(nil, nil)), WithTimeout(10*time.Millisecond))
+
+	_, err := client.Raw(context.Background(), "/meta/")
+
+	assert.ErrorIs(t, err, ErrTimeout)
+}
+
+func TestClient_Raw_contextDone(t *testing.T) {
+	client := New(WithOpener(fakeSerial(nil, nil)))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := client.Raw(ctx, "/meta/")
+
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestClient_Raw_missingDevice(t *testing.T) {
+	client := New(WithDevice(filepath.Join(t.TempDir(), "missing")))
+
+	_, err := client.Raw(context.Background(), "")
+
+	assert.Error(t, err)
+}